@@ -1,11 +1,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/natedogg/edgar/pkg/edgar"
 )
@@ -13,43 +17,244 @@ import (
 func main() {
 	// Define command line flags
 	var cik string
+	var ticker string
 	var quarterly bool
 	var ebitda bool
 	var ebitdaQuarterly bool
-	flag.StringVar(&cik, "cik", "", "Company CIK (Central Index Key) - required")
-	flag.BoolVar(&quarterly, "quarterly", false, "Get 4 most recent 10-Q filings and their cash flow metrics")
+	var explain bool
+	var mapping string
+	var annual bool
+	var years int
+	var ttm bool
+	flag.StringVar(&cik, "cik", "", "Company CIK (Central Index Key) - required unless a ticker is given")
+	flag.StringVar(&ticker, "ticker", "", "Company ticker symbol, e.g. AAPL, resolved to a CIK")
+	flag.BoolVar(&quarterly, "quarterly", false, "Get cash flow metrics for the 4 most recent quarters (10-Qs, and the 10-K for a fourth quarter)")
 	flag.BoolVar(&ebitda, "ebitda", false, "Calculate EBITDA for the most recent 10-Q filing")
-	flag.BoolVar(&ebitdaQuarterly, "ebitda-quarterly", false, "Calculate EBITDA for the 4 most recent 10-Q filings")
+	flag.BoolVar(&ebitdaQuarterly, "ebitda-quarterly", false, "Calculate EBITDA for the 4 most recent quarters (10-Qs, and the 10-K for a fourth quarter)")
+	flag.BoolVar(&annual, "annual", false, "Analyze annual reports (10-K, 20-F, 40-F) instead of 10-Qs; combine with -ebitda for EBITDA")
+	flag.IntVar(&years, "years", 3, "Number of fiscal years analyzed with -annual")
+	flag.BoolVar(&ttm, "ttm", false, "Calculate trailing-twelve-month cash flow and EBITDA as of the most recent report")
+	flag.BoolVar(&explain, "explain", false, "Show the XBRL fact behind every metric component")
+	flag.StringVar(&mapping, "mapping", "", "YAML or JSON file overriding the concepts metrics are read from")
 	flag.Parse()
 
+	// A positional argument is treated as a ticker or CIK
+	symbol := ticker
+	if symbol == "" {
+		symbol = flag.Arg(0)
+	}
+
 	// Validate required flag
-	if cik == "" {
-		fmt.Fprintf(os.Stderr, "Error: CIK is required\n")
-		fmt.Fprintf(os.Stderr, "Usage: %s -cik <CIK> [options]\n", os.Args[0])
+	if cik == "" && symbol == "" {
+		fmt.Fprintf(os.Stderr, "Error: CIK is required (or pass -ticker or a ticker symbol)\n")
+		fmt.Fprintf(os.Stderr, "Usage: %s [-cik <CIK> | -ticker <TICKER> | <TICKER>] [options]\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
-		fmt.Fprintf(os.Stderr, "  -quarterly          Get 4 most recent 10-Q cash flow metrics\n")
+		fmt.Fprintf(os.Stderr, "  -quarterly          Get cash flow metrics for the 4 most recent quarters\n")
 		fmt.Fprintf(os.Stderr, "  -ebitda            Calculate EBITDA for most recent 10-Q\n")
-		fmt.Fprintf(os.Stderr, "  -ebitda-quarterly  Calculate EBITDA for 4 most recent 10-Q filings\n")
+		fmt.Fprintf(os.Stderr, "  -ebitda-quarterly  Calculate EBITDA for the 4 most recent quarters\n")
+		fmt.Fprintf(os.Stderr, "  -annual            Analyze annual reports (10-K, 20-F, 40-F); with -ebitda for EBITDA\n")
+		fmt.Fprintf(os.Stderr, "  -years N           Number of fiscal years analyzed with -annual (default 3)\n")
+		fmt.Fprintf(os.Stderr, "  -ttm               Calculate trailing-twelve-month cash flow and EBITDA\n")
+		fmt.Fprintf(os.Stderr, "  -explain           Show the XBRL fact behind every metric component\n")
+		fmt.Fprintf(os.Stderr, "  -mapping <file>    Override the concepts metrics are read from (YAML or JSON)\n")
+		fmt.Fprintf(os.Stderr, "Environment:\n")
+		fmt.Fprintf(os.Stderr, "  SEC_USER_AGENT     Required: identifies you to the SEC, e.g. \"Sample Company admin@example.com\"\n")
 		fmt.Fprintf(os.Stderr, "Examples:\n")
 		fmt.Fprintf(os.Stderr, "  %s -cik 0000320193\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -ticker AAPL\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -cik 0000320193 -quarterly\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -cik 0000320193 -ebitda\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -cik 0000320193 -ebitda-quarterly\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -ebitda-quarterly AAPL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -ebitda -explain AAPL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -annual -years 5 AAPL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -annual -ebitda AAPL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -ttm AAPL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -quarterly -mapping mapping.yaml AAPL\n", os.Args[0])
 		os.Exit(1)
 	}
 
+	ctx := context.Background()
+	opts := []edgar.Option{edgar.WithRetryPolicy(edgar.DefaultRetryPolicy()), edgar.WithEnv()}
+	if mapping != "" {
+		concepts, err := edgar.LoadConceptMap(mapping)
+		if err != nil {
+			log.Fatalf("Error loading concept map: %v", err)
+		}
+		opts = append(opts, edgar.WithConceptMap(concepts))
+	}
+	client := edgar.NewClient(opts...)
+	if err := client.CheckUserAgent(); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if cik == "" {
+		// Resolve the ticker (or numeric CIK) through the SEC ticker files
+		resolved, err := tickerClient(opts).ResolveCIK(ctx, symbol)
+		if err != nil {
+			log.Fatalf("Error resolving %s to a CIK: %v", symbol, err)
+		}
+		cik = resolved
+	}
+
 	// Pad CIK with leading zeros if needed (SEC expects 10 digits)
 	if len(cik) < 10 {
 		cik = fmt.Sprintf("%010s", cik)
 	}
 
-	client := edgar.NewClient()
+	if ttm {
+		// Trailing-twelve-month analysis as of the most recent 10-Q or annual report
+		fmt.Printf("Fetching most recent report and calculating trailing twelve months for CIK: %s\n", cik)
+
+		metrics, err := client.GetTTMMetrics(ctx, cik)
+		if err != nil {
+			log.Fatalf("Error calculating trailing-twelve-month metrics: %v", err)
+		}
+
+		// Display the results
+		fmt.Printf("\nTrailing Twelve Months for %s\n", metrics.CompanyName)
+		fmt.Printf("=========================================\n")
+		fmt.Printf("CIK: %s\n", metrics.CIK)
+		fmt.Printf("As of: %s (%s %s)\n", metrics.AsOf, metrics.Form, metrics.AccessionNumber)
+		fmt.Println()
+
+		fmt.Printf("Cash Flow:\n")
+		fmt.Printf("----------\n")
+		printTTM("Net Cash from Operating Activities", metrics.CashFlow.NetCashFromOperatingActivities, metrics, edgar.MetricNetCashFromOperatingActivities)
+		printTTM("Capital Expenditures", metrics.CashFlow.CapitalExpenditures, metrics, edgar.MetricCapitalExpenditures)
+		fmt.Printf("Free Cash Flow (FCF): %s\n", formatMoney(metrics.CashFlow.FreeCashFlow, metrics.CashFlow.Currency))
+		printStatus("", metrics.CashFlow.MetricStatus, explain)
+		fmt.Println()
+
+		fmt.Printf("EBITDA:\n")
+		fmt.Printf("-------\n")
+		printTTM("Revenue", metrics.EBITDA.Revenue, metrics, edgar.MetricRevenue)
+		printTTM("Net Income", metrics.EBITDA.NetIncome, metrics, edgar.MetricNetIncome)
+		printTTM("Interest Expense", metrics.EBITDA.InterestExpense, metrics, edgar.MetricInterestExpense)
+		printTTM("Income Tax Expense", metrics.EBITDA.IncomeTaxExpense, metrics, edgar.MetricIncomeTaxExpense)
+		printTTM("Depreciation & Amortization", metrics.EBITDA.DepreciationAndAmortization, metrics, edgar.MetricDepreciationAndAmortization)
+		fmt.Printf("EBITDA: %s\n", formatMoney(metrics.EBITDA.EBITDA, metrics.EBITDA.Currency))
+		fmt.Printf("EBITDA Margin: %s\n", formatPercent(metrics.EBITDA.EBITDAMargin))
+		printStatus("", metrics.EBITDA.MetricStatus, explain)
+		fmt.Println()
+
+		// Also output as JSON for programmatic use
+		fmt.Println("JSON Output:")
+		fmt.Println("============")
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(metrics); err != nil {
+			log.Fatalf("Error encoding JSON response: %v", err)
+		}
+
+	} else if annual && (ebitda || ebitdaQuarterly) {
+		// Get annual EBITDA analysis for the most recent annual reports
+		fmt.Printf("Fetching %d most recent annual filings and EBITDA metrics for CIK: %s\n", years, cik)
+
+		analysis, err := client.GetAnnualEBITDAAnalysis(ctx, cik, years)
+		if err != nil {
+			log.Fatalf("Error getting annual EBITDA analysis: %v", err)
+		}
+
+		// Display the results
+		fmt.Printf("\nAnnual EBITDA Analysis for %s\n", analysis.CompanyName)
+		fmt.Printf("=======================================\n")
+		fmt.Printf("CIK: %s\n", analysis.CIK)
+		fmt.Printf("Number of years analyzed: %d\n\n", len(analysis.Years))
+
+		for i, year := range analysis.Years {
+			fmt.Printf("Year %d (%s):\n", i+1, year.Form)
+			fmt.Printf("----------\n")
+			fmt.Printf("  Filing Date: %s\n", year.FilingDate)
+			fmt.Printf("  Fiscal Year End: %s\n", year.ReportDate)
+			fmt.Printf("  Accession Number: %s\n", year.AccessionNumber)
+			fmt.Printf("  Revenue: %s\n", formatMoney(year.Revenue, year.Currency))
+			fmt.Printf("  Net Income: %s\n", formatMoney(year.NetIncome, year.Currency))
+			fmt.Printf("  Interest Expense: %s\n", formatMoney(year.InterestExpense, year.Currency))
+			fmt.Printf("  Income Tax Expense: %s\n", formatMoney(year.IncomeTaxExpense, year.Currency))
+			fmt.Printf("  Depreciation & Amortization: %s\n", formatMoney(year.DepreciationAndAmortization, year.Currency))
+			fmt.Printf("  EBITDA: %s\n", formatMoney(year.EBITDA, year.Currency))
+			fmt.Printf("  EBITDA Margin: %s\n", formatPercent(year.EBITDAMargin))
+			printStatus("  ", year.MetricStatus, explain)
+			fmt.Println()
+		}
+
+		// Calculate and display trends
+		if len(analysis.Years) > 1 {
+			fmt.Printf("Trends (Year 1 vs Year %d):\n", len(analysis.Years))
+			fmt.Printf("----------------------------\n")
+			latest := analysis.Years[0]
+			oldest := analysis.Years[len(analysis.Years)-1]
+
+			printChange("EBITDA", latest.EBITDA, oldest.EBITDA, latest.Currency)
+			printChange("Net Income", latest.NetIncome, oldest.NetIncome, latest.Currency)
+			printChange("Revenue", latest.Revenue, oldest.Revenue, latest.Currency)
+			fmt.Println()
+		}
+
+		// Also output as JSON for programmatic use
+		fmt.Println("JSON Output:")
+		fmt.Println("============")
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(analysis); err != nil {
+			log.Fatalf("Error encoding JSON response: %v", err)
+		}
+
+	} else if annual {
+		// Get annual cash flow analysis for the most recent annual reports
+		fmt.Printf("Fetching %d most recent annual filings and cash flow metrics for CIK: %s\n", years, cik)
+
+		analysis, err := client.GetAnnualCashFlowAnalysis(ctx, cik, years)
+		if err != nil {
+			log.Fatalf("Error getting annual cash flow analysis: %v", err)
+		}
+
+		// Display the results
+		fmt.Printf("\nAnnual Cash Flow Analysis for %s\n", analysis.CompanyName)
+		fmt.Printf("============================================\n")
+		fmt.Printf("CIK: %s\n", analysis.CIK)
+		fmt.Printf("Number of years analyzed: %d\n\n", len(analysis.Years))
+
+		for i, year := range analysis.Years {
+			fmt.Printf("Year %d (%s):\n", i+1, year.Form)
+			fmt.Printf("----------\n")
+			fmt.Printf("  Filing Date: %s\n", year.FilingDate)
+			fmt.Printf("  Fiscal Year End: %s\n", year.ReportDate)
+			fmt.Printf("  Accession Number: %s\n", year.AccessionNumber)
+			fmt.Printf("  Net Cash from Operating Activities: %s\n", formatMoney(year.NetCashFromOperatingActivities, year.Currency))
+			fmt.Printf("  Capital Expenditures: %s\n", formatMoney(year.CapitalExpenditures, year.Currency))
+			fmt.Printf("  Free Cash Flow (FCF): %s\n", formatMoney(year.FreeCashFlow, year.Currency))
+			printStatus("  ", year.MetricStatus, explain)
+			fmt.Println()
+		}
+
+		// Calculate and display trends
+		if len(analysis.Years) > 1 {
+			fmt.Printf("Trends (Year 1 vs Year %d):\n", len(analysis.Years))
+			fmt.Printf("----------------------------\n")
+			latest := analysis.Years[0]
+			oldest := analysis.Years[len(analysis.Years)-1]
+
+			printChange("Free Cash Flow", latest.FreeCashFlow, oldest.FreeCashFlow, latest.Currency)
+			printChange("Operating Cash Flow", latest.NetCashFromOperatingActivities, oldest.NetCashFromOperatingActivities, latest.Currency)
+			printChange("Capital Expenditures", latest.CapitalExpenditures, oldest.CapitalExpenditures, latest.Currency)
+			fmt.Println()
+		}
+
+		// Also output as JSON for programmatic use
+		fmt.Println("JSON Output:")
+		fmt.Println("============")
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(analysis); err != nil {
+			log.Fatalf("Error encoding JSON response: %v", err)
+		}
 
-	if ebitdaQuarterly {
-		// Get quarterly EBITDA analysis for 4 most recent 10-Q filings
-		fmt.Printf("Fetching 4 most recent 10-Q filings and EBITDA metrics for CIK: %s\n", cik)
+	} else if ebitdaQuarterly {
+		// Get quarterly EBITDA analysis for the 4 most recent quarters
+		fmt.Printf("Fetching 4 most recent quarterly filings and EBITDA metrics for CIK: %s\n", cik)
 
-		analysis, err := client.GetQuarterlyEBITDAAnalysis(cik)
+		analysis, err := client.GetQuarterlyEBITDAAnalysis(ctx, cik)
 		if err != nil {
 			log.Fatalf("Error getting quarterly EBITDA analysis: %v", err)
 		}
@@ -61,18 +266,19 @@ func main() {
 		fmt.Printf("Number of quarters analyzed: %d\n\n", len(analysis.Quarters))
 
 		for i, quarter := range analysis.Quarters {
-			fmt.Printf("Quarter %d:\n", i+1)
+			fmt.Printf("Quarter %d (%s):\n", i+1, quarter.Form)
 			fmt.Printf("----------\n")
 			fmt.Printf("  Filing Date: %s\n", quarter.FilingDate)
 			fmt.Printf("  Report Date: %s\n", quarter.ReportDate)
 			fmt.Printf("  Accession Number: %s\n", quarter.AccessionNumber)
-			fmt.Printf("  Revenue: $%.2f\n", quarter.Revenue)
-			fmt.Printf("  Net Income: $%.2f\n", quarter.NetIncome)
-			fmt.Printf("  Interest Expense: $%.2f\n", quarter.InterestExpense)
-			fmt.Printf("  Income Tax Expense: $%.2f\n", quarter.IncomeTaxExpense)
-			fmt.Printf("  Depreciation & Amortization: $%.2f\n", quarter.DepreciationAndAmortization)
-			fmt.Printf("  EBITDA: $%.2f\n", quarter.EBITDA)
-			fmt.Printf("  EBITDA Margin: %.2f%%\n", quarter.EBITDAMargin)
+			fmt.Printf("  Revenue: %s\n", formatMoney(quarter.Revenue, quarter.Currency))
+			fmt.Printf("  Net Income: %s\n", formatMoney(quarter.NetIncome, quarter.Currency))
+			fmt.Printf("  Interest Expense: %s\n", formatMoney(quarter.InterestExpense, quarter.Currency))
+			fmt.Printf("  Income Tax Expense: %s\n", formatMoney(quarter.IncomeTaxExpense, quarter.Currency))
+			fmt.Printf("  Depreciation & Amortization: %s\n", formatMoney(quarter.DepreciationAndAmortization, quarter.Currency))
+			fmt.Printf("  EBITDA: %s\n", formatMoney(quarter.EBITDA, quarter.Currency))
+			fmt.Printf("  EBITDA Margin: %s\n", formatPercent(quarter.EBITDAMargin))
+			printStatus("  ", quarter.MetricStatus, explain)
 			fmt.Println()
 		}
 
@@ -83,25 +289,18 @@ func main() {
 			latest := analysis.Quarters[0]
 			oldest := analysis.Quarters[len(analysis.Quarters)-1]
 
-			ebitdaChange := latest.EBITDA - oldest.EBITDA
-			ebitdaChangePercent := (ebitdaChange / oldest.EBITDA) * 100
-
-			fmt.Printf("  EBITDA Change: $%.2f (%.2f%%)\n", ebitdaChange, ebitdaChangePercent)
-
-			netIncomeChange := latest.NetIncome - oldest.NetIncome
-			netIncomeChangePercent := (netIncomeChange / oldest.NetIncome) * 100
-
-			fmt.Printf("  Net Income Change: $%.2f (%.2f%%)\n", netIncomeChange, netIncomeChangePercent)
+			printChange("EBITDA", latest.EBITDA, oldest.EBITDA, latest.Currency)
+			printChange("Net Income", latest.NetIncome, oldest.NetIncome, latest.Currency)
+			printChange("Revenue", latest.Revenue, oldest.Revenue, latest.Currency)
 
-			revenueChange := latest.Revenue - oldest.Revenue
-			revenueChangePercent := (revenueChange / oldest.Revenue) * 100
+			if latest.EBITDAMargin != nil && oldest.EBITDAMargin != nil {
+				marginChange := *latest.EBITDAMargin - *oldest.EBITDAMargin
 
-			fmt.Printf("  Revenue Change: $%.2f (%.2f%%)\n", revenueChange, revenueChangePercent)
-
-			marginChange := latest.EBITDAMargin - oldest.EBITDAMargin
-
-			fmt.Printf("  EBITDA Margin Change: %.2f%% to %.2f%% (%.2f percentage points)\n",
-				oldest.EBITDAMargin, latest.EBITDAMargin, marginChange)
+				fmt.Printf("  EBITDA Margin Change: %.2f%% to %.2f%% (%.2f percentage points)\n",
+					*oldest.EBITDAMargin, *latest.EBITDAMargin, marginChange)
+			} else {
+				fmt.Printf("  EBITDA Margin Change: N/A\n")
+			}
 			fmt.Println()
 		}
 
@@ -119,7 +318,7 @@ func main() {
 		fmt.Printf("Fetching most recent 10-Q filing and calculating EBITDA for CIK: %s\n", cik)
 
 		// Get the most recent 10-Q filing
-		filing, err := client.GetMostRecent10Q(cik)
+		filing, err := client.GetMostRecent10Q(ctx, cik)
 		if err != nil {
 			log.Fatalf("Error getting most recent 10-Q filing: %v", err)
 		}
@@ -133,7 +332,7 @@ func main() {
 
 		// Parse EBITDA metrics from the filing
 		fmt.Println("Calculating EBITDA...")
-		metrics, err := client.ParseEBITDAMetrics(cik, filing)
+		metrics, err := client.ParseEBITDAMetrics(ctx, cik, filing)
 		if err != nil {
 			log.Fatalf("Error parsing EBITDA metrics: %v", err)
 		}
@@ -150,13 +349,14 @@ func main() {
 
 		fmt.Printf("EBITDA Components:\n")
 		fmt.Printf("------------------\n")
-		fmt.Printf("Revenue: $%.2f\n", metrics.Revenue)
-		fmt.Printf("Net Income: $%.2f\n", metrics.NetIncome)
-		fmt.Printf("Interest Expense: $%.2f\n", metrics.InterestExpense)
-		fmt.Printf("Income Tax Expense: $%.2f\n", metrics.IncomeTaxExpense)
-		fmt.Printf("Depreciation & Amortization: $%.2f\n", metrics.DepreciationAndAmortization)
-		fmt.Printf("EBITDA: $%.2f\n", metrics.EBITDA)
-		fmt.Printf("EBITDA Margin: %.2f%%\n", metrics.EBITDAMargin)
+		fmt.Printf("Revenue: %s\n", formatMoney(metrics.Revenue, metrics.Currency))
+		fmt.Printf("Net Income: %s\n", formatMoney(metrics.NetIncome, metrics.Currency))
+		fmt.Printf("Interest Expense: %s\n", formatMoney(metrics.InterestExpense, metrics.Currency))
+		fmt.Printf("Income Tax Expense: %s\n", formatMoney(metrics.IncomeTaxExpense, metrics.Currency))
+		fmt.Printf("Depreciation & Amortization: %s\n", formatMoney(metrics.DepreciationAndAmortization, metrics.Currency))
+		fmt.Printf("EBITDA: %s\n", formatMoney(metrics.EBITDA, metrics.Currency))
+		fmt.Printf("EBITDA Margin: %s\n", formatPercent(metrics.EBITDAMargin))
+		printStatus("", metrics.MetricStatus, explain)
 		fmt.Println()
 
 		// Also output as JSON for programmatic use
@@ -169,10 +369,10 @@ func main() {
 		}
 
 	} else if quarterly {
-		// Get quarterly analysis for 4 most recent 10-Q filings
-		fmt.Printf("Fetching 4 most recent 10-Q filings and cash flow metrics for CIK: %s\n", cik)
+		// Get quarterly analysis for the 4 most recent quarters
+		fmt.Printf("Fetching 4 most recent quarterly filings and cash flow metrics for CIK: %s\n", cik)
 
-		analysis, err := client.GetQuarterlyCashFlowAnalysis(cik)
+		analysis, err := client.GetQuarterlyCashFlowAnalysis(ctx, cik)
 		if err != nil {
 			log.Fatalf("Error getting quarterly cash flow analysis: %v", err)
 		}
@@ -184,14 +384,15 @@ func main() {
 		fmt.Printf("Number of quarters analyzed: %d\n\n", len(analysis.Quarters))
 
 		for i, quarter := range analysis.Quarters {
-			fmt.Printf("Quarter %d:\n", i+1)
+			fmt.Printf("Quarter %d (%s):\n", i+1, quarter.Form)
 			fmt.Printf("----------\n")
 			fmt.Printf("  Filing Date: %s\n", quarter.FilingDate)
 			fmt.Printf("  Report Date: %s\n", quarter.ReportDate)
 			fmt.Printf("  Accession Number: %s\n", quarter.AccessionNumber)
-			fmt.Printf("  Net Cash from Operating Activities: $%.2f\n", quarter.NetCashFromOperatingActivities)
-			fmt.Printf("  Capital Expenditures: $%.2f\n", quarter.CapitalExpenditures)
-			fmt.Printf("  Free Cash Flow (FCF): $%.2f\n", quarter.FreeCashFlow)
+			fmt.Printf("  Net Cash from Operating Activities: %s\n", formatMoney(quarter.NetCashFromOperatingActivities, quarter.Currency))
+			fmt.Printf("  Capital Expenditures: %s\n", formatMoney(quarter.CapitalExpenditures, quarter.Currency))
+			fmt.Printf("  Free Cash Flow (FCF): %s\n", formatMoney(quarter.FreeCashFlow, quarter.Currency))
+			printStatus("  ", quarter.MetricStatus, explain)
 			fmt.Println()
 		}
 
@@ -202,20 +403,9 @@ func main() {
 			latest := analysis.Quarters[0]
 			oldest := analysis.Quarters[len(analysis.Quarters)-1]
 
-			fcfChange := latest.FreeCashFlow - oldest.FreeCashFlow
-			fcfChangePercent := (fcfChange / oldest.FreeCashFlow) * 100
-
-			fmt.Printf("  Free Cash Flow Change: $%.2f (%.2f%%)\n", fcfChange, fcfChangePercent)
-
-			opCashChange := latest.NetCashFromOperatingActivities - oldest.NetCashFromOperatingActivities
-			opCashChangePercent := (opCashChange / oldest.NetCashFromOperatingActivities) * 100
-
-			fmt.Printf("  Operating Cash Flow Change: $%.2f (%.2f%%)\n", opCashChange, opCashChangePercent)
-
-			capexChange := latest.CapitalExpenditures - oldest.CapitalExpenditures
-			capexChangePercent := (capexChange / oldest.CapitalExpenditures) * 100
-
-			fmt.Printf("  Capital Expenditures Change: $%.2f (%.2f%%)\n", capexChange, capexChangePercent)
+			printChange("Free Cash Flow", latest.FreeCashFlow, oldest.FreeCashFlow, latest.Currency)
+			printChange("Operating Cash Flow", latest.NetCashFromOperatingActivities, oldest.NetCashFromOperatingActivities, latest.Currency)
+			printChange("Capital Expenditures", latest.CapitalExpenditures, oldest.CapitalExpenditures, latest.Currency)
 			fmt.Println()
 		}
 
@@ -233,7 +423,7 @@ func main() {
 		fmt.Printf("Fetching most recent 10-Q filing for CIK: %s\n", cik)
 
 		// Get the most recent 10-Q filing
-		filing, err := client.GetMostRecent10Q(cik)
+		filing, err := client.GetMostRecent10Q(ctx, cik)
 		if err != nil {
 			log.Fatalf("Error getting most recent 10-Q filing: %v", err)
 		}
@@ -247,7 +437,7 @@ func main() {
 
 		// Parse cash flow metrics from the filing
 		fmt.Println("Parsing cash flow metrics...")
-		metrics, err := client.ParseCashFlowMetrics(cik, filing)
+		metrics, err := client.ParseCashFlowMetrics(ctx, cik, filing)
 		if err != nil {
 			log.Fatalf("Error parsing cash flow metrics: %v", err)
 		}
@@ -264,9 +454,10 @@ func main() {
 
 		fmt.Printf("Cash Flow Metrics:\n")
 		fmt.Printf("------------------\n")
-		fmt.Printf("Net Cash from Operating Activities: $%.2f\n", metrics.NetCashFromOperatingActivities)
-		fmt.Printf("Capital Expenditures: $%.2f\n", metrics.CapitalExpenditures)
-		fmt.Printf("Free Cash Flow (FCF): $%.2f\n", metrics.FreeCashFlow)
+		fmt.Printf("Net Cash from Operating Activities: %s\n", formatMoney(metrics.NetCashFromOperatingActivities, metrics.Currency))
+		fmt.Printf("Capital Expenditures: %s\n", formatMoney(metrics.CapitalExpenditures, metrics.Currency))
+		fmt.Printf("Free Cash Flow (FCF): %s\n", formatMoney(metrics.FreeCashFlow, metrics.Currency))
+		printStatus("", metrics.MetricStatus, explain)
 		fmt.Println()
 
 		// Also output as JSON for programmatic use
@@ -279,3 +470,136 @@ func main() {
 		}
 	}
 }
+
+// tickerClient returns a client resolving tickers that keeps the SEC ticker files on disk for a day:
+// in SEC_CACHE_DIR when set (see edgar.WithEnv), otherwise in edgar.DefaultCacheDir
+func tickerClient(opts []edgar.Option) *edgar.Client {
+	opts = append(slices.Clip(opts),
+		edgar.WithCacheTTL(edgar.PathTickersExchange, edgar.TickerFilesTTL),
+		edgar.WithCacheTTL(edgar.PathTickers, edgar.TickerFilesTTL),
+	)
+
+	if os.Getenv(edgar.EnvCacheDir) == "" {
+		dir, err := edgar.DefaultCacheDir()
+		var cache *edgar.FileCache
+		if err == nil {
+			cache, err = edgar.NewFileCache(dir)
+		}
+		if err != nil {
+			log.Printf("Warning: Could not cache the ticker files: %v", err)
+		} else {
+			opts = append(opts, edgar.WithCache(cache))
+		}
+	}
+
+	return edgar.NewClient(opts...)
+}
+
+// formatMoney formats a metric value in the currency it was reported in, or "N/A" when it is missing
+func formatMoney(v *float64, currency string) string {
+	if v == nil {
+		return "N/A"
+	}
+	return fmt.Sprintf("%s%.2f", currencyPrefix(currency), *v)
+}
+
+// currencyPrefix returns "$" for US dollars, or the ISO 4217 code followed by a space for other currencies
+func currencyPrefix(currency string) string {
+	if currency == "" || currency == "USD" {
+		return "$"
+	}
+	return currency + " "
+}
+
+// formatPercent formats a percentage metric, or "N/A" when it is missing
+func formatPercent(v *float64) string {
+	if v == nil {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.2f%%", *v)
+}
+
+// printChange prints the change of a metric between the oldest and latest periods
+func printChange(label string, latest, oldest *float64, currency string) {
+	if latest == nil || oldest == nil {
+		fmt.Printf("  %s Change: N/A\n", label)
+		return
+	}
+
+	change := *latest - *oldest
+	changePercent := (change / *oldest) * 100
+
+	fmt.Printf("  %s Change: %s%.2f (%.2f%%)\n", label, currencyPrefix(currency), change, changePercent)
+}
+
+// printTTM prints a trailing-twelve-month component with the method and periods it was computed from
+func printTTM(label string, value *float64, metrics *edgar.TTMMetrics, name string) {
+	if value == nil {
+		fmt.Printf("%s: %s\n", label, formatMoney(value, metrics.CashFlow.Currency))
+		return
+	}
+	fmt.Printf("%s: %s [%s: %s]\n", label, formatMoney(value, metrics.CashFlow.Currency), metrics.Methods[name], metrics.Periods[name])
+}
+
+// printStatus lists the components that were missing or derived from year-to-date values,
+// and with explain the facts every component was taken from
+func printStatus(indent string, status edgar.MetricStatus, explain bool) {
+	if status.Incomplete {
+		fmt.Printf("%s* Incomplete, not found: %s\n", indent, strings.Join(status.MissingComponents, ", "))
+	}
+
+	fields := make([]string, 0, len(status.MissingReasons))
+	for field := range status.MissingReasons {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		fmt.Printf("%s* %s %s\n", indent, field, status.MissingReasons[field])
+	}
+
+	fields = fields[:0]
+	for field := range status.Derivations {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		fmt.Printf("%s* %s derived from year-to-date values: %s\n", indent, field, status.Derivations[field])
+	}
+
+	fields = fields[:0]
+	for total := range status.IncompleteTotals {
+		fields = append(fields, total)
+	}
+	sort.Strings(fields)
+
+	for _, total := range fields {
+		fmt.Printf("%s* %s counts missing %s as zero\n", indent, total, strings.Join(status.IncompleteTotals[total], ", "))
+	}
+
+	fields = fields[:0]
+	for field := range status.Provenance {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		for _, source := range status.Provenance[field] {
+			if source.FromOtherFiling {
+				fmt.Printf("%s* %s not reported by this filing, taken from %s %s\n", indent, field, source.Form, source.AccessionNumber)
+			}
+		}
+	}
+
+	if !explain {
+		return
+	}
+
+	fmt.Printf("%sSources:\n", indent)
+	for _, field := range fields {
+		for _, source := range status.Provenance[field] {
+			fmt.Printf("%s  %s: %s = %.2f\n", indent, field, source, source.Value)
+		}
+	}
+}
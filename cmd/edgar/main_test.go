@@ -2,13 +2,21 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/natedogg/edgar/pkg/edgar"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -269,6 +277,95 @@ func TestFlagParsing(t *testing.T) {
 	}
 }
 
+func TestTickerFlagParsing(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedSymbol string
+		expectedEBITDA bool
+	}{
+		{
+			name:           "ticker flag",
+			args:           []string{"edgar", "-ticker", "AAPL"},
+			expectedSymbol: "AAPL",
+		},
+		{
+			name:           "positional ticker",
+			args:           []string{"edgar", "-ebitda", "AAPL"},
+			expectedSymbol: "AAPL",
+			expectedEBITDA: true,
+		},
+		{
+			name:           "ticker flag wins over positional argument",
+			args:           []string{"edgar", "-ticker", "MSFT", "AAPL"},
+			expectedSymbol: "MSFT",
+		},
+		{
+			name:           "no ticker",
+			args:           []string{"edgar", "-cik", "320193"},
+			expectedSymbol: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags()
+
+			// Mock os.Args
+			originalArgs := os.Args
+			os.Args = tt.args
+			defer func() {
+				os.Args = originalArgs
+			}()
+
+			var cik string
+			var ticker string
+			var ebitda bool
+
+			flag.StringVar(&cik, "cik", "", "Company CIK (Central Index Key) - required unless a ticker is given")
+			flag.StringVar(&ticker, "ticker", "", "Company ticker symbol, e.g. AAPL, resolved to a CIK")
+			flag.BoolVar(&ebitda, "ebitda", false, "Calculate EBITDA for the most recent 10-Q filing")
+			flag.Parse()
+
+			symbol := ticker
+			if symbol == "" {
+				symbol = flag.Arg(0)
+			}
+
+			assert.Equal(t, tt.expectedSymbol, symbol)
+			assert.Equal(t, tt.expectedEBITDA, ebitda)
+		})
+	}
+}
+
+func TestTickerClient_CachesTickerFiles(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the user cache directory does not follow XDG_CACHE_HOME on " + runtime.GOOS)
+	}
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"fields": ["cik", "name", "ticker", "exchange"], "data": [[320193, "Apple Inc.", "AAPL", "Nasdaq"]]}`)) // Ignoring write error in test
+	}))
+	defer server.Close()
+
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	t.Setenv(edgar.EnvCacheDir, "")
+
+	opts := []edgar.Option{edgar.WithArchivesURL(server.URL), edgar.WithUserAgent("Test Company test@example.com"), edgar.WithRateLimiter(nil)}
+
+	// Every CLI run creates a new client; only the first downloads the ticker file
+	for i := 0; i < 2; i++ {
+		cik, err := tickerClient(opts).ResolveCIK(context.Background(), "AAPL")
+		require.NoError(t, err)
+		assert.Equal(t, "0000320193", cik)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.DirExists(t, filepath.Join(cacheHome, "edgar"))
+}
+
 func TestUsageOutput(t *testing.T) {
 	resetFlags()
 
@@ -332,6 +429,7 @@ func TestBinaryExecution(t *testing.T) {
 	tests := []struct {
 		name           string
 		args           []string
+		userAgent      string
 		expectError    bool
 		expectContains []string
 	}{
@@ -352,9 +450,28 @@ func TestBinaryExecution(t *testing.T) {
 				"Error: CIK is required",
 			},
 		},
+		{
+			name:        "missing user agent",
+			args:        []string{"-cik", "320193"},
+			userAgent:   "",
+			expectError: true,
+			expectContains: []string{
+				"SEC_USER_AGENT",
+			},
+		},
+		{
+			name:        "invalid mapping file",
+			args:        []string{"-cik", "320193", "-mapping", "missing-mapping.yaml"},
+			userAgent:   "Test Company test@example.com",
+			expectError: true,
+			expectContains: []string{
+				"Error loading concept map",
+			},
+		},
 		{
 			name:        "invalid CIK format",
 			args:        []string{"-cik", "invalid"},
+			userAgent:   "Test Company test@example.com",
 			expectError: false, // Won't error on format, will error on API call
 			expectContains: []string{
 				"Fetching most recent 10-Q filing for CIK: 0000invalid",
@@ -365,6 +482,7 @@ func TestBinaryExecution(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cmd := exec.Command("../../bin/edgar-test", tt.args...)
+			cmd.Env = append(os.Environ(), "SEC_USER_AGENT="+tt.userAgent)
 			output, err := cmd.CombinedOutput()
 			outputStr := string(output)
 
@@ -485,6 +603,26 @@ func TestOutputFormatting(t *testing.T) {
 	}
 }
 
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    *float64
+		currency string
+		want     string
+	}{
+		{"US dollars", edgar.Float(50000), "USD", "$50000.00"},
+		{"unknown currency", edgar.Float(-5), "", "$-5.00"},
+		{"reporting currency", edgar.Float(50000), "EUR", "EUR 50000.00"},
+		{"missing", nil, "EUR", "N/A"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatMoney(tt.value, tt.currency))
+		})
+	}
+}
+
 // Test percentage formatting for EBITDA margin
 func TestPercentageFormatting(t *testing.T) {
 	tests := []struct {
@@ -550,3 +688,55 @@ type EBITDAMetrics struct {
 	EBITDA                      float64 `json:"ebitda"`
 	EBITDAMargin                float64 `json:"ebitdaMargin"`
 }
+
+func TestPrintStatus(t *testing.T) {
+	status := edgar.MetricStatus{
+		Incomplete:        true,
+		MissingComponents: []string{"interestExpense", "epsBasic"},
+		MissingReasons:    map[string]string{"epsBasic": "only reported for 12M to 2024-09-28"},
+		Derivations:       map[string]string{"depreciationAndAmortization": "6M to 2024-03-30 minus 3M to 2023-12-30"},
+		IncompleteTotals:  map[string][]string{"ebitda": {"interestExpense"}},
+		Provenance: map[string][]edgar.Provenance{
+			"revenue": {{
+				Taxonomy:        "us-gaap",
+				Tag:             "Revenues",
+				Unit:            "USD",
+				Start:           edgar.NewDate(2023, time.December, 31),
+				End:             edgar.NewDate(2024, time.March, 30),
+				AccessionNumber: "0000320193-24-000069",
+				Form:            "10-Q",
+				Filed:           edgar.NewDate(2024, time.May, 3),
+				Value:           90753000000,
+			}},
+		},
+	}
+
+	stdout, _ := captureOutput(func() { printStatus("", status, false) })
+	assert.Contains(t, stdout, "Incomplete, not found: interestExpense, epsBasic")
+	assert.Contains(t, stdout, "* epsBasic only reported for 12M to 2024-09-28")
+	assert.Contains(t, stdout, "depreciationAndAmortization derived from year-to-date values")
+	assert.Contains(t, stdout, "ebitda counts missing interestExpense as zero")
+	assert.NotContains(t, stdout, "Sources:")
+
+	stdout, _ = captureOutput(func() { printStatus("", status, true) })
+	assert.Contains(t, stdout, "Sources:")
+	assert.Contains(t, stdout, "revenue: us-gaap:Revenues (USD) 2023-12-31 to 2024-03-30 from 10-Q 0000320193-24-000069 filed 2024-05-03 = 90753000000.00")
+	assert.NotContains(t, stdout, "not reported by this filing")
+
+	status.Provenance["revenue"][0].FromOtherFiling = true
+	stdout, _ = captureOutput(func() { printStatus("", status, false) })
+	assert.Contains(t, stdout, "revenue not reported by this filing, taken from 10-Q 0000320193-24-000069")
+}
+
+func TestPrintTTM(t *testing.T) {
+	metrics := &edgar.TTMMetrics{
+		Methods: map[string]edgar.TTMMethod{"revenue": edgar.TTMYearToDate},
+		Periods: map[string]string{"revenue": "12M to 2023-09-30 plus 3M to 2023-12-30 minus 3M to 2022-12-31"},
+	}
+
+	stdout, _ := captureOutput(func() { printTTM("Revenue", edgar.Float(386), metrics, "revenue") })
+	assert.Equal(t, "Revenue: $386.00 [yearToDate: 12M to 2023-09-30 plus 3M to 2023-12-30 minus 3M to 2022-12-31]\n", stdout)
+
+	stdout, _ = captureOutput(func() { printTTM("Net Income", nil, metrics, "netIncome") })
+	assert.Equal(t, "Net Income: N/A\n", stdout)
+}
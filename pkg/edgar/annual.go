@@ -0,0 +1,134 @@
+package edgar
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// AnnualForms are the annual report forms: 10-K for domestic filers, 20-F and 40-F for foreign private issuers
+var AnnualForms = []string{"10-K", "20-F", "40-F"}
+
+// AnnualCashFlowAnalysis represents cash flow metrics over multiple fiscal years, most recent first
+type AnnualCashFlowAnalysis struct {
+	CompanyName string            `json:"companyName"`
+	CIK         string            `json:"cik"`
+	Years       []CashFlowMetrics `json:"years"`
+}
+
+// AnnualEBITDAAnalysis represents EBITDA metrics over multiple fiscal years, most recent first
+type AnnualEBITDAAnalysis struct {
+	CompanyName string          `json:"companyName"`
+	CIK         string          `json:"cik"`
+	Years       []EBITDAMetrics `json:"years"`
+}
+
+// GetMostRecentAnnualFilings finds the most recent annual reports (10-K, 20-F or 40-F), newest first
+func (c *Client) GetMostRecentAnnualFilings(ctx context.Context, cik string, years int) ([]Filing, error) {
+	if years < 1 {
+		return nil, fmt.Errorf("years must be at least 1, got %d", years)
+	}
+
+	filings, err := c.ListFilings(ctx, cik, FilingQuery{Forms: AnnualForms, Limit: years})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filings) == 0 {
+		return nil, fmt.Errorf("no annual filings found for CIK %s: %w", cik, ErrNoFilings)
+	}
+
+	return filings, nil
+}
+
+// GetAnnualCashFlowAnalysis retrieves cash flow metrics for the most recent annual reports, one per fiscal year.
+// Values are for the 12 months ending on each report date.
+func (c *Client) GetAnnualCashFlowAnalysis(ctx context.Context, cik string, years int) (*AnnualCashFlowAnalysis, error) {
+	filings, err := c.GetMostRecentAnnualFilings(ctx, cik, years)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent annual filings: %w", err)
+	}
+
+	// Get company facts once (we'll reuse this for all years)
+	facts, err := c.GetCompanyFacts(ctx, cik)
+	if err != nil {
+		return nil, fmt.Errorf("error getting company facts: %w", err)
+	}
+
+	analysis := &AnnualCashFlowAnalysis{
+		CompanyName: facts.Entity,
+		CIK:         facts.GetCIKString(),
+		Years:       make([]CashFlowMetrics, 0, len(filings)),
+	}
+	concepts := c.companyConcepts(ctx, cik)
+
+	for _, filing := range filings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		metrics, err := c.parseCashFlowMetrics(facts, &filing, DurationYear, concepts)
+		if err != nil {
+			log.Printf("Warning: Could not parse cash flow metrics for filing %s: %v", filing.AccessionNumber, err)
+			continue
+		}
+		analysis.Years = append(analysis.Years, *metrics)
+	}
+
+	if len(analysis.Years) == 0 {
+		return nil, fmt.Errorf("no cash flow metrics could be extracted from any annual filings")
+	}
+
+	return analysis, nil
+}
+
+// GetAnnualEBITDAAnalysis retrieves EBITDA metrics for the most recent annual reports, one per fiscal year.
+// Values are for the 12 months ending on each report date.
+func (c *Client) GetAnnualEBITDAAnalysis(ctx context.Context, cik string, years int) (*AnnualEBITDAAnalysis, error) {
+	filings, err := c.GetMostRecentAnnualFilings(ctx, cik, years)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent annual filings: %w", err)
+	}
+
+	// Get company facts once (we'll reuse this for all years)
+	facts, err := c.GetCompanyFacts(ctx, cik)
+	if err != nil {
+		return nil, fmt.Errorf("error getting company facts: %w", err)
+	}
+
+	analysis := &AnnualEBITDAAnalysis{
+		CompanyName: facts.Entity,
+		CIK:         facts.GetCIKString(),
+		Years:       make([]EBITDAMetrics, 0, len(filings)),
+	}
+	concepts := c.companyConcepts(ctx, cik)
+
+	for _, filing := range filings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		metrics, err := c.parseEBITDAMetrics(facts, &filing, DurationYear, concepts)
+		if err != nil {
+			log.Printf("Warning: Could not parse EBITDA metrics for filing %s: %v", filing.AccessionNumber, err)
+			continue
+		}
+		analysis.Years = append(analysis.Years, *metrics)
+	}
+
+	if len(analysis.Years) == 0 {
+		return nil, fmt.Errorf("no EBITDA metrics could be extracted from any annual filings")
+	}
+
+	return analysis, nil
+}
+
+// ParseAnnualCashFlowMetricsFromFacts extracts cash flow metrics for the fiscal year ending on the filing's
+// report date using pre-fetched company facts
+func (c *Client) ParseAnnualCashFlowMetricsFromFacts(facts *CompanyFacts, filing *Filing) (*CashFlowMetrics, error) {
+	return c.parseCashFlowMetrics(facts, filing, DurationYear, c.conceptsFor(facts.GetCIKString(), ""))
+}
+
+// ParseAnnualEBITDAMetricsFromFacts extracts EBITDA components for the fiscal year ending on the filing's
+// report date using pre-fetched company facts
+func (c *Client) ParseAnnualEBITDAMetricsFromFacts(facts *CompanyFacts, filing *Filing) (*EBITDAMetrics, error) {
+	return c.parseEBITDAMetrics(facts, filing, DurationYear, c.conceptsFor(facts.GetCIKString(), ""))
+}
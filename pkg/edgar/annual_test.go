@@ -0,0 +1,205 @@
+package edgar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// annualServer serves submissions with two 10-Ks and a 10-Q, and facts for both fiscal years
+func annualServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, PathSubmissions):
+			w.Write([]byte(`{
+				"cik": "320193",
+				"name": "Apple Inc.",
+				"filings": {"recent": {
+					"accessionNumber": ["q1", "fy24", "fy23"],
+					"filingDate": ["2025-01-31", "2024-11-01", "2023-11-03"],
+					"reportDate": ["2024-12-28", "2024-09-28", "2023-09-30"],
+					"form": ["10-Q", "10-K", "10-K"]
+				}}
+			}`))
+		case strings.HasPrefix(r.URL.Path, PathCompanyFacts):
+			w.Write([]byte(`{
+				"cik": 320193,
+				"entityName": "Apple Inc.",
+				"facts": {"us-gaap": {
+					"NetCashProvidedByUsedInOperatingActivities": {"units": {"USD": [
+						{"start": "2022-09-25", "end": "2023-09-30", "val": 110, "accn": "fy23", "form": "10-K", "filed": "2023-11-03"},
+						{"start": "2022-09-25", "end": "2023-09-30", "val": 111, "accn": "fy24", "form": "10-K", "filed": "2024-11-01"},
+						{"start": "2023-10-01", "end": "2024-06-29", "val": 91, "accn": "q3", "form": "10-Q", "filed": "2024-08-02"},
+						{"start": "2023-10-01", "end": "2024-09-28", "val": 118, "accn": "fy24", "form": "10-K", "filed": "2024-11-01"}
+					]}},
+					"PaymentsToAcquirePropertyPlantAndEquipment": {"units": {"USD": [
+						{"start": "2022-09-25", "end": "2023-09-30", "val": 11, "accn": "fy23", "form": "10-K", "filed": "2023-11-03"},
+						{"start": "2023-10-01", "end": "2024-09-28", "val": 9, "accn": "fy24", "form": "10-K", "filed": "2024-11-01"}
+					]}},
+					"Revenues": {"units": {"USD": [
+						{"start": "2023-10-01", "end": "2024-09-28", "val": 391, "accn": "fy24", "form": "10-K", "filed": "2024-11-01"}
+					]}},
+					"NetIncomeLoss": {"units": {"USD": [
+						{"start": "2023-10-01", "end": "2024-09-28", "val": 94, "accn": "fy24", "form": "10-K", "filed": "2024-11-01"}
+					]}}
+				}}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_GetAnnualCashFlowAnalysis(t *testing.T) {
+	client := NewClient(WithBaseURL(annualServer(t).URL), WithRateLimiter(nil))
+
+	analysis, err := client.GetAnnualCashFlowAnalysis(context.Background(), "0000320193", 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Apple Inc.", analysis.CompanyName)
+	require.Len(t, analysis.Years, 2) // The 10-Q is not an annual report
+
+	latest := analysis.Years[0]
+	assert.Equal(t, "fy24", latest.AccessionNumber)
+	assert.Equal(t, "10-K", latest.Form)
+	assert.Equal(t, Float(118), latest.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(9), latest.CapitalExpenditures)
+	assert.Equal(t, Float(109), latest.FreeCashFlow)
+	assert.Empty(t, latest.Derivations)
+
+	// Values come from the filing's own facts rather than later comparatives
+	prior := analysis.Years[1]
+	assert.Equal(t, "2023-09-30", prior.ReportDate)
+	assert.Equal(t, Float(110), prior.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(99), prior.FreeCashFlow)
+}
+
+func TestClient_GetAnnualEBITDAAnalysis(t *testing.T) {
+	client := NewClient(WithBaseURL(annualServer(t).URL), WithRateLimiter(nil))
+
+	analysis, err := client.GetAnnualEBITDAAnalysis(context.Background(), "0000320193", 1)
+	require.NoError(t, err)
+
+	require.Len(t, analysis.Years, 1)
+	year := analysis.Years[0]
+	assert.Equal(t, "2024-09-28", year.ReportDate)
+	assert.Equal(t, Float(391), year.Revenue)
+	assert.Equal(t, Float(94), year.NetIncome)
+	assert.Equal(t, Float(94), year.EBITDA)
+	assert.True(t, year.Incomplete)
+	assert.Equal(t, "2023-10-01", year.Provenance[MetricRevenue][0].Start.String())
+}
+
+func TestClient_GetMostRecentAnnualFilings(t *testing.T) {
+	client := NewClient(WithBaseURL(annualServer(t).URL), WithRateLimiter(nil))
+
+	filings, err := client.GetMostRecentAnnualFilings(context.Background(), "0000320193", 1)
+	require.NoError(t, err)
+	require.Len(t, filings, 1)
+	assert.Equal(t, "fy24", filings[0].AccessionNumber)
+
+	_, err = client.GetMostRecentAnnualFilings(context.Background(), "0000320193", 0)
+	assert.ErrorContains(t, err, "years must be at least 1")
+}
+
+func TestClient_GetMostRecentAnnualFilings_None(t *testing.T) {
+	server := createMockServer(getMockCompanySubmissions(), http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(nil))
+
+	_, err := client.GetMostRecentAnnualFilings(context.Background(), "0000320193", 3)
+	assert.True(t, errors.Is(err, ErrNoFilings))
+}
+
+func TestClient_GetAnnualAnalysis_20F(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, PathSubmissions):
+			w.Write([]byte(`{
+				"cik": "937966",
+				"name": "ASML Holding N.V.",
+				"filings": {"recent": {
+					"accessionNumber": ["fy24", "6k"],
+					"filingDate": ["2025-02-12", "2025-01-29"],
+					"reportDate": ["2024-12-31", ""],
+					"form": ["20-F", "6-K"]
+				}}
+			}`))
+		case strings.HasPrefix(r.URL.Path, PathCompanyFacts):
+			w.Write([]byte(`{
+				"cik": 937966,
+				"entityName": "ASML Holding N.V.",
+				"facts": {
+					"dei": {"EntityPublicFloat": {"units": {"USD": [
+						{"end": "2024-06-30", "val": 350000, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+					]}}},
+					"ifrs-full": {
+						"CashFlowsFromUsedInOperatingActivities": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 11200, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}},
+						"PurchaseOfPropertyPlantAndEquipmentClassifiedAsInvestingActivities": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 2100, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}},
+						"Revenue": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 28300, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}},
+						"ProfitLoss": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 7600, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}},
+						"FinanceCosts": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 100, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}},
+						"IncomeTaxExpenseContinuingOperations": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 1300, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}},
+						"DepreciationAndAmortisationExpense": {"units": {"EUR": [
+							{"start": "2024-01-01", "end": "2024-12-31", "val": 900, "accn": "fy24", "form": "20-F", "filed": "2025-02-12"}
+						]}}
+					}
+				}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithRateLimiter(nil))
+
+	cashFlow, err := client.GetAnnualCashFlowAnalysis(context.Background(), "0000937966", 3)
+	require.NoError(t, err)
+	require.Len(t, cashFlow.Years, 1)
+
+	year := cashFlow.Years[0]
+	assert.Equal(t, "20-F", year.Form)
+	assert.Equal(t, "EUR", year.Currency)
+	assert.Equal(t, Float(11200), year.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(2100), year.CapitalExpenditures)
+	assert.Equal(t, Float(9100), year.FreeCashFlow)
+	assert.False(t, year.Incomplete)
+
+	source := year.Provenance[MetricCapitalExpenditures][0]
+	assert.Equal(t, "ifrs-full", source.Taxonomy)
+	assert.Equal(t, "EUR", source.Unit)
+
+	ebitda, err := client.GetAnnualEBITDAAnalysis(context.Background(), "0000937966", 3)
+	require.NoError(t, err)
+	require.Len(t, ebitda.Years, 1)
+
+	metrics := ebitda.Years[0]
+	assert.Equal(t, "EUR", metrics.Currency)
+	assert.Equal(t, Float(28300), metrics.Revenue)
+	assert.Equal(t, Float(7600), metrics.NetIncome)
+	assert.Equal(t, Float(9900), metrics.EBITDA)
+	assert.Equal(t, "ifrs-full:ProfitLoss (EUR) 2024-01-01 to 2024-12-31 from 20-F fy24 filed 2025-02-12", metrics.Provenance[MetricNetIncome][0].String())
+}
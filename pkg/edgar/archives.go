@@ -0,0 +1,278 @@
+package edgar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PathArchives is the request path of filing directories on the archives host
+const PathArchives = "/Archives/edgar/data/"
+
+// FilingIndex lists the documents of a single filing
+type FilingIndex struct {
+	CIK             string           `json:"cik"`
+	AccessionNumber string           `json:"accessionNumber"`
+	Documents       []FilingDocument `json:"documents"`
+}
+
+// FilingDocument is a file within a filing directory
+type FilingDocument struct {
+	Sequence     int    `json:"sequence,omitempty"` // Position in the filing, 0 for files outside the submission (e.g. index pages)
+	Name         string `json:"name"`
+	Type         string `json:"type,omitempty"` // Document type, e.g. 10-Q, EX-31.1, GRAPHIC
+	Description  string `json:"description,omitempty"`
+	Size         int64  `json:"size"`
+	LastModified string `json:"lastModified,omitempty"`
+	URL          string `json:"url"`
+}
+
+// Document returns the document with the given file name
+func (idx *FilingIndex) Document(name string) (FilingDocument, bool) {
+	for _, doc := range idx.Documents {
+		if doc.Name == name {
+			return doc, true
+		}
+	}
+	return FilingDocument{}, false
+}
+
+// Exhibits returns the filing's exhibits (documents of type EX-*)
+func (idx *FilingIndex) Exhibits() []FilingDocument {
+	var exhibits []FilingDocument
+	for _, doc := range idx.Documents {
+		if strings.HasPrefix(doc.Type, "EX-") {
+			exhibits = append(exhibits, doc)
+		}
+	}
+	return exhibits
+}
+
+// filingDir returns the archives URL of a filing directory, e.g.
+// https://www.sec.gov/Archives/edgar/data/320193/000032019324000006
+func (c *Client) filingDir(cik, accession string) (string, error) {
+	n, err := strconv.ParseInt(cik, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIK %q: %w", cik, err)
+	}
+	return fmt.Sprintf("%s%s%d/%s", c.archivesURL, PathArchives, n, strings.ReplaceAll(accession, "-", "")), nil
+}
+
+// GetFilingIndex lists every document in a filing. Sizes come from the directory's index.json;
+// document types and descriptions come from the filing's -index.htm page when it is available.
+func (c *Client) GetFilingIndex(ctx context.Context, cik, accession string) (*FilingIndex, error) {
+	dir, err := c.filingDir(cik, accession)
+	if err != nil {
+		return nil, err
+	}
+
+	url := dir + "/index.json"
+	body, err := c.makeRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing struct {
+		Directory struct {
+			Item []struct {
+				Name         string `json:"name"`
+				Size         string `json:"size"`
+				LastModified string `json:"last-modified"`
+			} `json:"item"`
+		} `json:"directory"`
+	}
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, &DecodeError{URL: url, Err: err}
+	}
+
+	index := &FilingIndex{CIK: cik, AccessionNumber: accession}
+	for _, item := range listing.Directory.Item {
+		size, _ := strconv.ParseInt(item.Size, 10, 64) // Sizes are blank for generated pages
+		index.Documents = append(index.Documents, FilingDocument{
+			Name:         item.Name,
+			Size:         size,
+			LastModified: item.LastModified,
+			URL:          dir + "/" + item.Name,
+		})
+	}
+
+	details, err := c.getFilingIndexDetails(ctx, dir, accession)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Printf("Warning: Could not get document types for %s: %v", accession, err)
+	}
+
+	for i, doc := range index.Documents {
+		if detail, ok := details[doc.Name]; ok {
+			index.Documents[i].Sequence = detail.Sequence
+			index.Documents[i].Type = detail.Type
+			index.Documents[i].Description = detail.Description
+		}
+	}
+
+	// Submission documents in sequence order, followed by the remaining files
+	sort.SliceStable(index.Documents, func(i, j int) bool {
+		si, sj := index.Documents[i].Sequence, index.Documents[j].Sequence
+		if si == 0 || sj == 0 {
+			return si != 0 && sj == 0
+		}
+		return si < sj
+	})
+
+	return index, nil
+}
+
+var (
+	tableRowPattern  = regexp.MustCompile(`(?is)<tr[^>]*>(.*?)</tr>`)
+	tableCellPattern = regexp.MustCompile(`(?is)<td[^>]*>(.*?)</td>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<[^>]+>`)
+)
+
+// getFilingIndexDetails parses the document table of a filing's -index.htm page,
+// whose columns are Seq, Description, Document, Type and Size
+func (c *Client) getFilingIndexDetails(ctx context.Context, dir, accession string) (map[string]FilingDocument, error) {
+	body, err := c.makeRequest(ctx, dir+"/"+accession+"-index.htm")
+	if err != nil {
+		return nil, err
+	}
+
+	details := make(map[string]FilingDocument)
+	for _, row := range tableRowPattern.FindAllStringSubmatch(string(body), -1) {
+		cells := tableCellPattern.FindAllStringSubmatch(row[1], -1)
+		if len(cells) < 4 {
+			continue
+		}
+
+		text := make([]string, len(cells))
+		for i, cell := range cells {
+			text[i] = strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(cell[1], " ")))
+		}
+
+		// The document cell may carry an "iXBRL" marker after the file name
+		fields := strings.Fields(text[2])
+		if len(fields) == 0 {
+			continue
+		}
+
+		seq, _ := strconv.Atoi(text[0]) // Blank for the complete submission text file
+		details[fields[0]] = FilingDocument{
+			Sequence:    seq,
+			Description: text[1],
+			Name:        fields[0],
+			Type:        text[3],
+		}
+	}
+
+	return details, nil
+}
+
+// OpenArchive opens a streaming GET request for any URL on the archives host, using the client's
+// User-Agent, rate limiter and retry policy. Gzip responses are decompressed transparently.
+// The client's timeout only bounds the wait for the response, not reading the body; use ctx to
+// cancel a download. The caller must close the returned reader.
+func (c *Client) OpenArchive(ctx context.Context, url string) (io.ReadCloser, error) {
+	resp, err := c.doRequestWith(ctx, c.streamingClient(), url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return responseBody(resp)
+}
+
+// DownloadDocument streams a document of a filing to w and returns the number of bytes written
+func (c *Client) DownloadDocument(ctx context.Context, cik, accession, name string, w io.Writer) (int64, error) {
+	dir, err := c.filingDir(cik, accession)
+	if err != nil {
+		return 0, err
+	}
+	return c.download(ctx, dir+"/"+name, w)
+}
+
+// DownloadPrimaryDocument streams the filing's primary document to w
+func (c *Client) DownloadPrimaryDocument(ctx context.Context, cik string, filing *Filing, w io.Writer) (int64, error) {
+	if filing.PrimaryDocument == "" {
+		return 0, fmt.Errorf("filing %s has no primary document", filing.AccessionNumber)
+	}
+	return c.DownloadDocument(ctx, cik, filing.AccessionNumber, filing.PrimaryDocument, w)
+}
+
+// DownloadFullSubmission streams the complete submission text file, which contains every document of the filing
+func (c *Client) DownloadFullSubmission(ctx context.Context, cik, accession string, w io.Writer) (int64, error) {
+	return c.DownloadDocument(ctx, cik, accession, accession+".txt", w)
+}
+
+// SaveDocument downloads a document of a filing to path. The file is only created once the download completes.
+func (c *Client) SaveDocument(ctx context.Context, cik, accession, name, path string) error {
+	dir, err := c.filingDir(cik, accession)
+	if err != nil {
+		return err
+	}
+	return c.save(ctx, dir+"/"+name, path)
+}
+
+// SaveExhibits downloads every exhibit in the index into dir and returns the paths written
+func (c *Client) SaveExhibits(ctx context.Context, index *FilingIndex, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory: %w", err)
+	}
+
+	var paths []string
+	for _, exhibit := range index.Exhibits() {
+		path := filepath.Join(dir, filepath.Base(exhibit.Name))
+		if err := c.SaveDocument(ctx, index.CIK, index.AccessionNumber, exhibit.Name, path); err != nil {
+			return paths, fmt.Errorf("error saving exhibit %s: %w", exhibit.Name, err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// save downloads url to a temporary file next to path and renames it into place once complete
+func (c *Client) save(ctx context.Context, url, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() // No-op once renamed
+
+	if _, err := c.download(ctx, url, tmp); err != nil {
+		_ = tmp.Close() // Ignoring close error, download already failed
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	return nil
+}
+
+// download streams url to w
+func (c *Client) download(ctx context.Context, url string, w io.Writer) (int64, error) {
+	body, err := c.OpenArchive(ctx, url)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = body.Close() }() // Ignoring close error
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, fmt.Errorf("error downloading %s: %w", url, err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,240 @@
+package edgar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockFilingDir = "/Archives/edgar/data/320193/000032019324000006"
+
+// Mock index.json directory listing of a filing
+func getMockFilingIndexJSON() string {
+	return `{
+		"directory": {
+			"item": [
+				{"last-modified": "2024-02-01 18:03:43", "name": "0000320193-24-000006-index-headers.html", "type": "text.gif", "size": ""},
+				{"last-modified": "2024-02-01 18:03:43", "name": "0000320193-24-000006.txt", "type": "text.gif", "size": "6072543"},
+				{"last-modified": "2024-02-01 18:03:43", "name": "a10-qexhibit3112302023.htm", "type": "text.gif", "size": "10233"},
+				{"last-modified": "2024-02-01 18:03:43", "name": "aapl-20231230.htm", "type": "text.gif", "size": "1234567"}
+			],
+			"name": "/Archives/edgar/data/320193/000032019324000006",
+			"parent-dir": "/Archives/edgar/data/320193/"
+		}
+	}`
+}
+
+// Mock -index.htm page of a filing
+func getMockFilingIndexHTML() string {
+	return `<html><body>
+		<table class="tableFile" summary="Document Format Files">
+			<tr><th scope="col">Seq</th><th scope="col">Description</th><th scope="col">Document</th><th scope="col">Type</th><th scope="col">Size</th></tr>
+			<tr><td scope="row">1</td><td scope="row">10-Q</td><td scope="row"><a href="/ix?doc=/Archives/edgar/data/320193/000032019324000006/aapl-20231230.htm">aapl-20231230.htm</a> &nbsp;&nbsp;<span style="color: green">iXBRL</span></td><td scope="row">10-Q</td><td scope="row">1234567</td></tr>
+			<tr class="evenRow"><td scope="row">2</td><td scope="row">CEO Certification &amp; Exhibit</td><td scope="row"><a href="/Archives/edgar/data/320193/000032019324000006/a10-qexhibit3112302023.htm">a10-qexhibit3112302023.htm</a></td><td scope="row">EX-31.1</td><td scope="row">10233</td></tr>
+			<tr><td scope="row">&nbsp;</td><td scope="row">Complete submission text file</td><td scope="row"><a href="/Archives/edgar/data/320193/000032019324000006/0000320193-24-000006.txt">0000320193-24-000006.txt</a></td><td scope="row">&nbsp;</td><td scope="row">6072543</td></tr>
+		</table>
+	</body></html>`
+}
+
+// createArchivesServer serves a filing directory, its documents and index pages
+func createArchivesServer(withIndexPage bool) *httptest.Server {
+	files := map[string]string{
+		mockFilingDir + "/index.json":                 getMockFilingIndexJSON(),
+		mockFilingDir + "/aapl-20231230.htm":          "<html>10-Q</html>",
+		mockFilingDir + "/a10-qexhibit3112302023.htm": "<html>EX-31.1</html>",
+		mockFilingDir + "/0000320193-24-000006.txt":   "<SEC-DOCUMENT>full submission</SEC-DOCUMENT>",
+	}
+	if withIndexPage {
+		files[mockFilingDir+"/0000320193-24-000006-index.htm"] = getMockFilingIndexHTML()
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body)) // Ignoring write error in test
+	}))
+}
+
+func TestClient_GetFilingIndex(t *testing.T) {
+	server := createArchivesServer(true)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL))
+	index, err := client.GetFilingIndex(context.Background(), mockCIK, "0000320193-24-000006")
+	require.NoError(t, err)
+
+	require.Len(t, index.Documents, 4)
+
+	primary := index.Documents[0]
+	assert.Equal(t, 1, primary.Sequence)
+	assert.Equal(t, "aapl-20231230.htm", primary.Name)
+	assert.Equal(t, "10-Q", primary.Type)
+	assert.Equal(t, int64(1234567), primary.Size)
+	assert.Equal(t, server.URL+mockFilingDir+"/aapl-20231230.htm", primary.URL)
+
+	exhibits := index.Exhibits()
+	require.Len(t, exhibits, 1)
+	assert.Equal(t, "EX-31.1", exhibits[0].Type)
+	assert.Equal(t, "CEO Certification & Exhibit", exhibits[0].Description)
+	assert.Equal(t, 2, exhibits[0].Sequence)
+
+	doc, ok := index.Document("0000320193-24-000006.txt")
+	require.True(t, ok)
+	assert.Equal(t, "Complete submission text file", doc.Description)
+	assert.Equal(t, int64(6072543), doc.Size)
+
+	headers, ok := index.Document("0000320193-24-000006-index-headers.html")
+	require.True(t, ok)
+	assert.Equal(t, int64(0), headers.Size)
+	assert.Empty(t, headers.Type)
+}
+
+func TestClient_GetFilingIndex_WithoutIndexPage(t *testing.T) {
+	server := createArchivesServer(false)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL))
+	index, err := client.GetFilingIndex(context.Background(), mockCIK, "0000320193-24-000006")
+	require.NoError(t, err)
+
+	assert.Len(t, index.Documents, 4)
+	assert.Empty(t, index.Exhibits())
+}
+
+func TestClient_DownloadDocuments(t *testing.T) {
+	server := createArchivesServer(true)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL))
+	ctx := context.Background()
+
+	var primary bytes.Buffer
+	n, err := client.DownloadPrimaryDocument(ctx, mockCIK, &Filing{
+		AccessionNumber: "0000320193-24-000006",
+		PrimaryDocument: "aapl-20231230.htm",
+	}, &primary)
+	require.NoError(t, err)
+	assert.Equal(t, "<html>10-Q</html>", primary.String())
+	assert.Equal(t, int64(primary.Len()), n)
+
+	var full bytes.Buffer
+	_, err = client.DownloadFullSubmission(ctx, mockCIK, "0000320193-24-000006", &full)
+	require.NoError(t, err)
+	assert.Contains(t, full.String(), "full submission")
+
+	_, err = client.DownloadDocument(ctx, mockCIK, "0000320193-24-000006", "missing.htm", &bytes.Buffer{})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = client.DownloadPrimaryDocument(ctx, mockCIK, &Filing{AccessionNumber: "0000320193-24-000006"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestClient_SaveExhibits(t *testing.T) {
+	server := createArchivesServer(true)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL))
+	index, err := client.GetFilingIndex(context.Background(), mockCIK, "0000320193-24-000006")
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "exhibits")
+	paths, err := client.SaveExhibits(context.Background(), index, dir)
+	require.NoError(t, err)
+	require.Len(t, paths, 1)
+
+	data, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "<html>EX-31.1</html>", string(data))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files should be left behind")
+}
+
+func TestClient_OpenArchive_Gzip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, userAgent, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(strings.Repeat("edgar ", 100))) // Ignoring write error in test
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	body, err := client.OpenArchive(context.Background(), server.URL+"/Archives/edgar/full-index/company.idx")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, strings.Repeat("edgar ", 100), buf.String())
+}
+
+// createSlowServer streams body in chunks, flushing each one after delay, so reading the whole
+// body takes longer than a short client timeout
+func createSlowServer(body []byte, chunks int, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		size := (len(body) + chunks - 1) / chunks
+		for start := 0; start < len(body); start += size {
+			end := min(start+size, len(body))
+			_, _ = w.Write(body[start:end]) // Ignoring write error in test
+			w.(http.Flusher).Flush()
+			time.Sleep(delay)
+		}
+	}))
+}
+
+func TestClient_OpenArchive_StreamsPastTimeout(t *testing.T) {
+	content := []byte(strings.Repeat("<SEC-DOCUMENT>", 1000))
+	server := createSlowServer(content, 5, 40*time.Millisecond)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL), WithRateLimiter(nil), WithRetryPolicy(RetryPolicy{}), WithTimeout(100*time.Millisecond))
+	ctx := context.Background()
+
+	var full bytes.Buffer
+	n, err := client.DownloadFullSubmission(ctx, mockCIK, "0000320193-24-000006", &full)
+	require.NoError(t, err, "the timeout should not cut off reading the body")
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, full.Bytes())
+
+	path := filepath.Join(t.TempDir(), "aapl-20231230.htm")
+	require.NoError(t, client.SaveDocument(ctx, mockCIK, "0000320193-24-000006", "aapl-20231230.htm", path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// API requests keep the overall timeout
+	_, err = client.makeRequest(ctx, server.URL+"/api/xbrl/companyfacts/CIK0000320193.json")
+	assert.Error(t, err)
+}
+
+func TestClient_OpenArchive_HeaderTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithRateLimiter(nil), WithRetryPolicy(RetryPolicy{}), WithTimeout(50*time.Millisecond))
+	_, err := client.OpenArchive(context.Background(), server.URL+"/Archives/edgar/full-index/company.idx")
+	assert.Error(t, err, "the timeout should still bound the wait for the response headers")
+}
@@ -0,0 +1,91 @@
+package edgar
+
+import (
+	"fmt"
+	"log"
+)
+
+// BalanceSheet represents balance sheet items as of a filing's report date. Values that could not be found are nil.
+type BalanceSheet struct {
+	CompanyName               string   `json:"companyName"`
+	CIK                       string   `json:"cik"`
+	FilingDate                string   `json:"filingDate"`
+	ReportDate                string   `json:"reportDate"`
+	Form                      string   `json:"form"`
+	AccessionNumber           string   `json:"accessionNumber"`
+	Currency                  string   `json:"currency"` // Reporting currency of the company facts, e.g. "USD"
+	Cash                      *float64 `json:"cash"`     // Cash and cash equivalents
+	ShortTermInvestments      *float64 `json:"shortTermInvestments"`
+	Receivables               *float64 `json:"receivables"`
+	Inventory                 *float64 `json:"inventory"`
+	TotalCurrentAssets        *float64 `json:"totalCurrentAssets"`
+	PropertyPlantAndEquipment *float64 `json:"propertyPlantAndEquipment"` // Net of accumulated depreciation
+	Goodwill                  *float64 `json:"goodwill"`
+	TotalAssets               *float64 `json:"totalAssets"`
+	TotalCurrentLiabilities   *float64 `json:"totalCurrentLiabilities"`
+	ShortTermDebt             *float64 `json:"shortTermDebt"` // Including the current portion of long-term debt
+	LongTermDebt              *float64 `json:"longTermDebt"`
+	TotalLiabilities          *float64 `json:"totalLiabilities"`
+	StockholdersEquity        *float64 `json:"stockholdersEquity"`
+	MetricStatus
+}
+
+// ParseBalanceSheet extracts the balance sheet as of the filing's report date using pre-fetched company facts.
+// Items are read from instant facts only, trying the candidates of the client's concept map in order.
+func (c *Client) ParseBalanceSheet(facts *CompanyFacts, filing *Filing) (*BalanceSheet, error) {
+	bs := &BalanceSheet{
+		CompanyName:     facts.Entity,
+		CIK:             facts.GetCIKString(),
+		FilingDate:      filing.FilingDate.String(),
+		ReportDate:      filing.ReportDate.String(),
+		Form:            filing.Form,
+		AccessionNumber: filing.AccessionNumber,
+		Currency:        facts.ReportingCurrency(),
+	}
+
+	if filing.ReportDate.IsZero() {
+		return nil, fmt.Errorf("filing %s has no report date", filing.AccessionNumber)
+	}
+
+	if err := c.extractBalanceSheetData(facts, c.conceptsFor(facts.GetCIKString(), ""), bs, c.forFiling(filing, DurationInstant)); err != nil {
+		return nil, fmt.Errorf("error extracting balance sheet data: %w", err)
+	}
+
+	return bs, nil
+}
+
+// extractBalanceSheetData extracts the balance sheet items for the instant selected by sel from company facts
+func (c *Client) extractBalanceSheetData(facts *CompanyFacts, concepts map[string][]ConceptCandidate, bs *BalanceSheet, sel FactSelector) error {
+	if facts.Facts == nil {
+		return fmt.Errorf("facts data is nil")
+	}
+
+	if err := checkTaxonomies(facts, concepts); err != nil {
+		return err
+	}
+
+	for _, item := range []struct {
+		name  string
+		value **float64
+	}{
+		{MetricCash, &bs.Cash},
+		{MetricShortTermInvestments, &bs.ShortTermInvestments},
+		{MetricReceivables, &bs.Receivables},
+		{MetricInventory, &bs.Inventory},
+		{MetricTotalCurrentAssets, &bs.TotalCurrentAssets},
+		{MetricPropertyPlantAndEquipment, &bs.PropertyPlantAndEquipment},
+		{MetricGoodwill, &bs.Goodwill},
+		{MetricTotalAssets, &bs.TotalAssets},
+		{MetricTotalCurrentLiabilities, &bs.TotalCurrentLiabilities},
+		{MetricShortTermDebt, &bs.ShortTermDebt},
+		{MetricLongTermDebt, &bs.LongTermDebt},
+		{MetricTotalLiabilities, &bs.TotalLiabilities},
+		{MetricStockholdersEquity, &bs.StockholdersEquity},
+	} {
+		if err := c.extractComponent(facts, concepts, sel, item.name, item.value, &bs.MetricStatus); err != nil {
+			log.Printf("Warning: Could not extract %s: %v", item.name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package edgar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ParseBalanceSheet(t *testing.T) {
+	q1 := NewDate(2023, time.December, 30)
+	filed := NewDate(2024, time.February, 2)
+
+	instant := func(val float64) *Concept {
+		return &Concept{Units: map[string][]Fact{"USD": {{End: q1, Val: val, Accn: "q1", Form: "10-Q", Filed: filed}}}}
+	}
+
+	facts := &CompanyFacts{CIK: "320193", Entity: "Apple Inc.", Facts: map[string]Taxonomy{"us-gaap": {
+		"CashAndCashEquivalentsAtCarryingValue": {Units: map[string][]Fact{"USD": {
+			{End: q1, Val: 40, Accn: "q1", Form: "10-Q", Filed: filed},
+			// The same balance as a comparative in a later filing
+			{End: q1, Val: 41, Accn: "q2", Form: "10-Q", Filed: NewDate(2024, time.May, 3)},
+		}}},
+		"MarketableSecuritiesCurrent":  instant(33),
+		"AccountsReceivableNetCurrent": instant(23),
+		"InventoryNet":                 instant(6),
+		"AssetsCurrent":                instant(143),
+		"PropertyPlantAndEquipmentNet": instant(43),
+		"Assets":                       instant(353),
+		"LiabilitiesCurrent":           instant(133),
+		"DebtCurrent":                  instant(12),
+		// Only the total including the current portion was reported
+		"LongTermDebt": instant(106),
+		"Liabilities":  instant(279),
+		"StockholdersEquity": {Units: map[string][]Fact{"USD": {
+			// Duration facts, e.g. from the statement of equity, are not balances
+			{Start: NewDate(2023, time.October, 1), End: q1, Val: 1, Accn: "q1", Form: "10-Q", Filed: filed},
+			{End: q1, Val: 74, Accn: "q1", Form: "10-Q", Filed: filed},
+		}}},
+	}}}
+
+	bs, err := NewClient().ParseBalanceSheet(facts, &Filing{AccessionNumber: "q1", Form: "10-Q", ReportDate: q1})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2023-12-30", bs.ReportDate)
+	assert.Equal(t, Float(40), bs.Cash)
+	assert.Equal(t, Float(33), bs.ShortTermInvestments)
+	assert.Equal(t, Float(23), bs.Receivables)
+	assert.Equal(t, Float(6), bs.Inventory)
+	assert.Equal(t, Float(143), bs.TotalCurrentAssets)
+	assert.Equal(t, Float(43), bs.PropertyPlantAndEquipment)
+	assert.Nil(t, bs.Goodwill)
+	assert.Equal(t, Float(353), bs.TotalAssets)
+	assert.Equal(t, Float(133), bs.TotalCurrentLiabilities)
+	assert.Equal(t, Float(12), bs.ShortTermDebt)
+	assert.Equal(t, Float(106), bs.LongTermDebt)
+	assert.Equal(t, Float(279), bs.TotalLiabilities)
+	assert.Equal(t, Float(74), bs.StockholdersEquity)
+
+	assert.True(t, bs.Incomplete)
+	assert.Equal(t, []string{MetricGoodwill}, bs.MissingComponents)
+
+	source := bs.Provenance[MetricLongTermDebt][0]
+	assert.Equal(t, "LongTermDebt", source.Tag)
+	assert.True(t, source.Start.IsZero())
+	assert.Equal(t, "us-gaap:LongTermDebt (USD) as of 2023-12-30 from 10-Q q1 filed 2024-02-02", source.String())
+}
+
+func TestClient_ParseBalanceSheet_Errors(t *testing.T) {
+	client := NewClient()
+	q1 := NewDate(2023, time.December, 30)
+
+	_, err := client.ParseBalanceSheet(&CompanyFacts{}, &Filing{AccessionNumber: "x"})
+	assert.ErrorContains(t, err, "has no report date")
+
+	_, err = client.ParseBalanceSheet(&CompanyFacts{}, &Filing{AccessionNumber: "x", ReportDate: q1})
+	assert.ErrorContains(t, err, "facts data is nil")
+
+	_, err = client.ParseBalanceSheet(&CompanyFacts{Facts: map[string]Taxonomy{"dei": {}}}, &Filing{AccessionNumber: "x", ReportDate: q1})
+	assert.ErrorContains(t, err, "ifrs-full, us-gaap taxonomy not found")
+}
@@ -0,0 +1,187 @@
+package edgar
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BulkKind identifies one of the SEC's nightly bulk archives
+type BulkKind int
+
+const (
+	BulkCompanyFacts BulkKind = iota + 1 // companyfacts.zip: one CIK##########.json per company, as served by GetCompanyFacts
+	BulkSubmissions                      // submissions.zip: CIK##########.json plus older pages, as served by GetCompanySubmissions
+)
+
+// Request paths of the bulk archives on the archives host
+const (
+	PathBulkCompanyFacts = "/Archives/edgar/daily-index/xbrl/companyfacts.zip"
+	PathBulkSubmissions  = "/Archives/edgar/daily-index/bulkdata/submissions.zip"
+)
+
+// archivePath returns the request path of the bulk archive
+func (k BulkKind) archivePath() string {
+	if k == BulkSubmissions {
+		return PathBulkSubmissions
+	}
+	return PathBulkCompanyFacts
+}
+
+// apiPath returns the request path of the data API endpoint mirrored by the archive's entries
+func (k BulkKind) apiPath() string {
+	if k == BulkSubmissions {
+		return PathSubmissions
+	}
+	return PathCompanyFacts
+}
+
+// DownloadBulkArchive downloads a bulk archive to dest. The archives are large (gigabytes),
+// so the body is streamed to disk and dest is only created once the download completes. Like OpenArchive,
+// the client's timeout only bounds the wait for the response; ctx cancels the download.
+func (c *Client) DownloadBulkArchive(ctx context.Context, kind BulkKind, dest string) error {
+	return c.save(ctx, c.archivesURL+kind.archivePath(), dest)
+}
+
+// BulkArchive is a downloaded bulk archive. Entries are decompressed lazily when read,
+// and a BulkArchive is safe for concurrent use.
+type BulkArchive struct {
+	kind    BulkKind
+	zr      *zip.ReadCloser
+	entries map[string]*zip.File
+}
+
+// OpenBulkArchive opens a bulk archive of the given kind, e.g. one saved by DownloadBulkArchive
+func OpenBulkArchive(path string, kind BulkKind) (*BulkArchive, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening bulk archive: %w", err)
+	}
+
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		entries[f.Name] = f
+	}
+
+	return &BulkArchive{kind: kind, zr: zr, entries: entries}, nil
+}
+
+// Close closes the underlying zip file
+func (b *BulkArchive) Close() error {
+	return b.zr.Close()
+}
+
+// Kind returns the kind of the archive
+func (b *BulkArchive) Kind() BulkKind {
+	return b.kind
+}
+
+var bulkCompanyEntry = regexp.MustCompile(`^CIK(\d{10})\.json$`)
+
+// CIKs returns the 10-digit CIKs of every company in the archive, in ascending order
+func (b *BulkArchive) CIKs() []string {
+	var ciks []string
+	for name := range b.entries {
+		if m := bulkCompanyEntry.FindStringSubmatch(name); m != nil {
+			ciks = append(ciks, m[1])
+		}
+	}
+	sort.Strings(ciks)
+	return ciks
+}
+
+// ReadEntry returns the decompressed contents of the named entry, e.g. "CIK0000320193.json"
+func (b *BulkArchive) ReadEntry(name string) ([]byte, error) {
+	f, ok := b.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("bulk archive entry %s: %w", name, ErrNotFound)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error opening bulk archive entry %s: %w", name, err)
+	}
+	defer func() { _ = rc.Close() }() // Ignoring close error
+
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bulk archive entry %s: %w", name, err)
+	}
+
+	return body, nil
+}
+
+// CompanyFacts decodes the company facts of cik from a companyfacts archive
+func (b *BulkArchive) CompanyFacts(cik string) (*CompanyFacts, error) {
+	if b.kind != BulkCompanyFacts {
+		return nil, fmt.Errorf("not a companyfacts archive")
+	}
+
+	var facts CompanyFacts
+	if err := b.decodeCompany(cik, &facts); err != nil {
+		return nil, err
+	}
+	return &facts, nil
+}
+
+// CompanySubmissions decodes the submissions of cik from a submissions archive
+func (b *BulkArchive) CompanySubmissions(cik string) (*CompanySubmissions, error) {
+	if b.kind != BulkSubmissions {
+		return nil, fmt.Errorf("not a submissions archive")
+	}
+
+	var submissions CompanySubmissions
+	if err := b.decodeCompany(cik, &submissions); err != nil {
+		return nil, err
+	}
+	return &submissions, nil
+}
+
+// decodeCompany decodes the CIK##########.json entry of cik into v
+func (b *BulkArchive) decodeCompany(cik string, v interface{}) error {
+	n, err := strconv.ParseInt(cik, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid CIK %q: %w", cik, err)
+	}
+
+	name := fmt.Sprintf("CIK%010d.json", n)
+	body, err := b.ReadEntry(name)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return &DecodeError{URL: name, Err: err}
+	}
+	return nil
+}
+
+// bulkRequest serves a data API URL from a configured bulk archive. A configured archive is
+// authoritative for its endpoint: companies missing from the snapshot are reported as ErrNotFound
+// rather than fetched, so analyses run fully offline.
+func (c *Client) bulkRequest(rawURL string) ([]byte, bool, error) {
+	p := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		p = u.Path
+	}
+
+	for _, archive := range c.bulk {
+		prefix := archive.kind.apiPath()
+		if !strings.HasPrefix(p, prefix) || strings.Contains(strings.TrimPrefix(p, prefix), "/") {
+			continue
+		}
+
+		body, err := archive.ReadEntry(path.Base(p))
+		return body, true, err
+	}
+
+	return nil, false, nil
+}
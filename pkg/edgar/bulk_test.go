@@ -0,0 +1,157 @@
+package edgar
+
+import (
+	"archive/zip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createBulkArchive writes a zip file with the given entries and returns its path
+func createBulkArchive(t *testing.T, entries map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "bulk.zip")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	zw := zip.NewWriter(f)
+	for name, body := range entries {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	return path
+}
+
+func TestBulkArchive(t *testing.T) {
+	path := createBulkArchive(t, map[string]string{
+		"CIK0000320193.json": getMockCompanyFacts(),
+		"CIK0000789019.json": `{"cik": 789019, "entityName": "MICROSOFT CORPORATION", "facts": {}}`,
+		"README.txt":         "not a company",
+	})
+
+	archive, err := OpenBulkArchive(path, BulkCompanyFacts)
+	require.NoError(t, err)
+	defer func() { _ = archive.Close() }()
+
+	assert.Equal(t, []string{"0000320193", "0000789019"}, archive.CIKs())
+
+	facts, err := archive.CompanyFacts("320193")
+	require.NoError(t, err)
+	assert.Equal(t, "Apple Inc.", facts.Entity)
+
+	_, err = archive.CompanyFacts("1")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = archive.CompanySubmissions(mockCIK)
+	assert.Error(t, err, "companyfacts archive cannot serve submissions")
+}
+
+func TestClient_WithBulkArchive_Offline(t *testing.T) {
+	factsArchive, err := OpenBulkArchive(createBulkArchive(t, map[string]string{
+		"CIK0000320193.json": getMockCompanyFacts(),
+	}), BulkCompanyFacts)
+	require.NoError(t, err)
+	defer func() { _ = factsArchive.Close() }()
+
+	submissionsArchive, err := OpenBulkArchive(createBulkArchive(t, map[string]string{
+		"CIK0000320193.json": getMockCompanySubmissions(),
+	}), BulkSubmissions)
+	require.NoError(t, err)
+	defer func() { _ = submissionsArchive.Close() }()
+
+	// Nothing listens on the base URL, so any network request would fail
+	client := NewClient(
+		WithBaseURL("http://127.0.0.1:1"),
+		WithBulkArchive(factsArchive),
+		WithBulkArchive(submissionsArchive),
+	)
+
+	analysis, err := client.GetQuarterlyCashFlowAnalysis(context.Background(), mockCIK)
+	require.NoError(t, err)
+	assert.Equal(t, "Apple Inc.", analysis.CompanyName)
+	assert.NotEmpty(t, analysis.Quarters)
+
+	_, err = client.GetCompanyFacts(context.Background(), "0000789019")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Endpoints without an archive still use the network
+	_, err = client.GetCompanyConcept(context.Background(), mockCIK, "us-gaap", "Revenues")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, uint64(1), client.Stats().Requests)
+}
+
+func TestClient_DownloadBulkArchive(t *testing.T) {
+	source := createBulkArchive(t, map[string]string{
+		"CIK0000320193.json": getMockCompanySubmissions(),
+	})
+	data, err := os.ReadFile(source)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != PathBulkSubmissions {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data) // Ignoring write error in test
+	}))
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL))
+	dest := filepath.Join(t.TempDir(), "submissions.zip")
+	require.NoError(t, client.DownloadBulkArchive(context.Background(), BulkSubmissions, dest))
+
+	archive, err := OpenBulkArchive(dest, BulkSubmissions)
+	require.NoError(t, err)
+	defer func() { _ = archive.Close() }()
+
+	submissions, err := archive.CompanySubmissions(mockCIK)
+	require.NoError(t, err)
+	assert.Equal(t, "Apple Inc.", submissions.Name)
+
+	err = client.DownloadBulkArchive(context.Background(), BulkCompanyFacts, filepath.Join(t.TempDir(), "companyfacts.zip"))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_DownloadBulkArchive_StreamsPastTimeout(t *testing.T) {
+	source := createBulkArchive(t, map[string]string{
+		"CIK0000320193.json": getMockCompanySubmissions(),
+	})
+	data, err := os.ReadFile(source)
+	require.NoError(t, err)
+
+	server := createSlowServer(data, 5, 40*time.Millisecond)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL), WithRateLimiter(nil), WithTimeout(100*time.Millisecond))
+	dest := filepath.Join(t.TempDir(), "submissions.zip")
+	require.NoError(t, client.DownloadBulkArchive(context.Background(), BulkSubmissions, dest))
+
+	archive, err := OpenBulkArchive(dest, BulkSubmissions)
+	require.NoError(t, err)
+	defer func() { _ = archive.Close() }()
+
+	submissions, err := archive.CompanySubmissions(mockCIK)
+	require.NoError(t, err)
+	assert.Equal(t, "Apple Inc.", submissions.Name)
+
+	// Cancelling the context still aborts the download
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	err = client.DownloadBulkArchive(ctx, BulkSubmissions, filepath.Join(t.TempDir(), "cancelled.zip"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
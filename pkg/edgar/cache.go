@@ -0,0 +1,258 @@
+package edgar
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached response body together with its HTTP validators
+type CacheEntry struct {
+	Body         []byte    `json:"-"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	StoredAt     time.Time `json:"storedAt"`
+}
+
+// Cache stores response bodies keyed by request URL.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry) error
+}
+
+// cachedRequest serves url from the cache while its TTL has not expired,
+// and otherwise revalidates it with a conditional request
+func (c *Client) cachedRequest(ctx context.Context, rawURL string) ([]byte, error) {
+	entry, ok := c.cache.Get(rawURL)
+	if ok && time.Since(entry.StoredAt) < c.cacheTTL(rawURL) {
+		c.stats.cacheHits.Add(1)
+		return entry.Body, nil
+	}
+
+	header := http.Header{}
+	if ok {
+		if entry.ETag != "" {
+			header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	body, resp, err := c.fetch(ctx, rawURL, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		c.stats.cacheRevalidated.Add(1)
+		refreshed := *entry
+		refreshed.StoredAt = time.Now()
+		c.storeCacheEntry(rawURL, &refreshed)
+		return refreshed.Body, nil
+	}
+
+	c.stats.cacheMisses.Add(1)
+	c.storeCacheEntry(rawURL, &CacheEntry{
+		Body:         body,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+	})
+
+	return body, nil
+}
+
+// storeCacheEntry writes an entry to the cache, logging rather than failing the request on error
+func (c *Client) storeCacheEntry(key string, entry *CacheEntry) {
+	if err := c.cache.Set(key, entry); err != nil {
+		log.Printf("Warning: Could not cache response for %s: %v", key, err)
+	}
+}
+
+// cacheTTL returns the TTL configured for the longest path prefix matching rawURL
+func (c *Client) cacheTTL(rawURL string) time.Duration {
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+
+	var ttl time.Duration
+	longest := -1
+	for prefix, d := range c.cacheTTLs {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			ttl = d
+			longest = len(prefix)
+		}
+	}
+
+	return ttl
+}
+
+// MemoryCache is an in-memory least-recently-used Cache
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // front = most recently used
+	items      map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewMemoryCache creates an LRU cache holding at most maxEntries responses (unbounded if maxEntries <= 0)
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry for key and marks it as recently used
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	m.order.MoveToFront(elem)
+
+	return elem.Value.(*memoryCacheItem).entry, true
+}
+
+// Set stores entry under key, evicting the least recently used entry if the cache is full
+func (m *MemoryCache) Set(key string, entry *CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.items[key]; ok {
+		elem.Value.(*memoryCacheItem).entry = entry
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.items[key] = m.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+
+	if m.maxEntries > 0 && m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.items, oldest.Value.(*memoryCacheItem).key)
+	}
+
+	return nil
+}
+
+// Len returns the number of cached entries
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.order.Len()
+}
+
+// FileCache is a Cache that stores each response as one file in a directory, named by the hash of its key:
+// a line of JSON with the validators, followed by the response body
+type FileCache struct {
+	dir string
+}
+
+// DefaultCacheDir returns the edgar directory in the user's cache directory, e.g. ~/.cache/edgar on Linux
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "edgar"), nil
+}
+
+// NewFileCache creates a filesystem cache rooted at dir, creating the directory if needed
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+// Get reads the entry for key from disk
+func (f *FileCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	meta, body, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, false
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return nil, false
+	}
+	entry.Body = body
+
+	return &entry, true
+}
+
+// Set writes the entry for key to disk. The validators and the body are written to one file that is
+// renamed into place, so a concurrent Get reads either the old entry or the new one, never a mix.
+func (f *FileCache) Set(key string, entry *CacheEntry) error {
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding cache metadata: %w", err)
+	}
+
+	data := make([]byte, 0, len(meta)+1+len(entry.Body))
+	data = append(data, meta...)
+	data = append(data, '\n')
+	data = append(data, entry.Body...)
+
+	return writeFileAtomic(f.path(key), data)
+}
+
+// path returns the file path used for key
+func (f *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".entry")
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("error creating cache file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }() // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close() // Ignoring close error, write already failed
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing cache file: %w", err)
+	}
+
+	return nil
+}
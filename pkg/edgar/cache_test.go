@@ -0,0 +1,214 @@
+package edgar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createETagServer serves body with the given ETag and answers matching If-None-Match with 304
+func createETagServer(body, etag string) (*httptest.Server, *int32, *int32) {
+	var full, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		_, _ = fmt.Fprint(w, body) // Ignoring write error in test
+	}))
+	return server, &full, &notModified
+}
+
+func TestClient_Cache_Revalidates(t *testing.T) {
+	server, full, notModified := createETagServer(getMockCompanyFacts(), `"v1"`)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithCache(NewMemoryCache(10)))
+
+	for i := 0; i < 3; i++ {
+		facts, err := client.GetCompanyFacts(context.Background(), mockCIK)
+		require.NoError(t, err)
+		assert.Equal(t, "Apple Inc.", facts.Entity)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(full))
+	assert.Equal(t, int32(2), atomic.LoadInt32(notModified))
+
+	stats := client.Stats()
+	assert.Equal(t, uint64(1), stats.CacheMisses)
+	assert.Equal(t, uint64(2), stats.CacheRevalidated)
+	assert.Equal(t, uint64(0), stats.CacheHits)
+}
+
+func TestClient_Cache_TTL(t *testing.T) {
+	server, full, notModified := createETagServer(getMockCompanyFacts(), `"v1"`)
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithCache(NewMemoryCache(10)),
+		WithCacheTTL(PathCompanyFacts, time.Hour),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.GetCompanyFacts(context.Background(), mockCIK)
+		require.NoError(t, err)
+	}
+
+	// Submissions have no TTL configured, so they are revalidated
+	_, err := client.GetCompanySubmissions(context.Background(), mockCIK)
+	require.NoError(t, err)
+	_, err = client.GetCompanySubmissions(context.Background(), mockCIK)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(full))
+	assert.Equal(t, int32(1), atomic.LoadInt32(notModified))
+
+	stats := client.Stats()
+	assert.Equal(t, uint64(2), stats.CacheHits)
+	assert.Equal(t, uint64(2), stats.CacheMisses)
+	assert.Equal(t, uint64(1), stats.CacheRevalidated)
+}
+
+func TestClient_cacheTTL(t *testing.T) {
+	client := NewClient(
+		WithCacheTTL("", time.Minute),
+		WithCacheTTL("/api/xbrl/", time.Hour),
+		WithCacheTTL(PathCompanyFacts, 24*time.Hour),
+	)
+
+	assert.Equal(t, 24*time.Hour, client.cacheTTL("https://data.sec.gov/api/xbrl/companyfacts/CIK0000320193.json"))
+	assert.Equal(t, time.Hour, client.cacheTTL("https://data.sec.gov/api/xbrl/companyconcept/CIK0000320193/us-gaap/Revenues.json"))
+	assert.Equal(t, time.Minute, client.cacheTTL("https://data.sec.gov/submissions/CIK0000320193.json"))
+}
+
+func TestFileCache(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	require.NoError(t, err)
+
+	_, ok := cache.Get("https://data.sec.gov/submissions/CIK0000320193.json")
+	assert.False(t, ok)
+
+	stored := &CacheEntry{
+		Body:         []byte(`{"cik": "320193"}`),
+		ETag:         `"abc"`,
+		LastModified: "Mon, 01 Jan 2024 00:00:00 GMT",
+		StoredAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, cache.Set("https://data.sec.gov/submissions/CIK0000320193.json", stored))
+
+	entry, ok := cache.Get("https://data.sec.gov/submissions/CIK0000320193.json")
+	require.True(t, ok)
+	assert.Equal(t, stored.Body, entry.Body)
+	assert.Equal(t, stored.ETag, entry.ETag)
+	assert.Equal(t, stored.LastModified, entry.LastModified)
+	assert.True(t, stored.StoredAt.Equal(entry.StoredAt))
+}
+
+func TestFileCache_OverwriteIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir)
+	require.NoError(t, err)
+
+	const key = "https://data.sec.gov/api/xbrl/companyfacts/CIK0000320193.json"
+	versions := []*CacheEntry{
+		{Body: []byte("v1\nbody"), ETag: `"v1"`},
+		{Body: []byte("v2\nbody"), ETag: `"v2"`},
+	}
+	require.NoError(t, cache.Set(key, versions[0]))
+
+	// Readers racing with writers must always see a body together with its own ETag
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			assert.NoError(t, cache.Set(key, versions[i%2]))
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		entry, ok := cache.Get(key)
+		require.True(t, ok)
+		assert.Equal(t, strings.Trim(entry.ETag, `"`)+"\nbody", string(entry.Body))
+	}
+	close(done)
+	wg.Wait()
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestDefaultCacheDir(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the user cache directory does not follow XDG_CACHE_HOME on " + runtime.GOOS)
+	}
+	home := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", home)
+
+	dir, err := DefaultCacheDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "edgar"), dir)
+}
+
+func TestClient_FileCache_SurvivesRestart(t *testing.T) {
+	server, full, notModified := createETagServer(getMockCompanySubmissions(), `"v1"`)
+	defer server.Close()
+
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		cache, err := NewFileCache(dir)
+		require.NoError(t, err)
+
+		client := NewClient(WithBaseURL(server.URL), WithCache(cache))
+		submissions, err := client.GetCompanySubmissions(context.Background(), mockCIK)
+		require.NoError(t, err)
+		assert.Equal(t, "Apple Inc.", submissions.Name)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(full))
+	assert.Equal(t, int32(1), atomic.LoadInt32(notModified))
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+
+	require.NoError(t, cache.Set("a", &CacheEntry{Body: []byte("a")}))
+	require.NoError(t, cache.Set("b", &CacheEntry{Body: []byte("b")}))
+
+	_, ok := cache.Get("a") // a is now more recently used than b
+	require.True(t, ok)
+
+	require.NoError(t, cache.Set("c", &CacheEntry{Body: []byte("c")}))
+
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "b should have been evicted")
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+}
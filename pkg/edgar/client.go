@@ -2,102 +2,261 @@ package edgar
 
 import (
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	baseURL   = "https://data.sec.gov"
-	userAgent = "Your Company Name yourname@example.com" // Replace with your details
+	baseURL     = "https://data.sec.gov"
+	archivesURL = "https://www.sec.gov"
+	userAgent   = "Your Company Name yourname@example.com" // Placeholder, see CheckUserAgent
+)
+
+// Request paths of the EDGAR data API, usable as prefixes for WithCacheTTL
+const (
+	PathCompanyFacts   = "/api/xbrl/companyfacts/"
+	PathCompanyConcept = "/api/xbrl/companyconcept/"
+	PathFrames         = "/api/xbrl/frames/"
+	PathSubmissions    = "/submissions/"
 )
 
 // Client represents an EDGAR API client
 type Client struct {
-	httpClient *http.Client
-	userAgent  string
+	httpClient  *http.Client
+	userAgent   string
+	baseURL     string
+	archivesURL string
+	limiter     *RateLimiter
+	retryPolicy RetryPolicy
+	cache       Cache
+	cacheTTLs   map[string]time.Duration
+	bulk        []*BulkArchive
+	stats       clientStats
+	concepts    *ConceptMap
+	fallback    bool     // Accept other filings' facts for a period the filing did not report, see WithFilingFallback
+	sics        sync.Map // CIK without leading zeros -> SIC code, from submissions
+
+	tickersOnce sync.Once
+	tickers     *TickerResolver
+
+	streamOnce   sync.Once
+	streamClient *http.Client // httpClient without the overall timeout, see streamingClient
 }
 
-// NewClient creates a new EDGAR API client
-func NewClient() *Client {
-	return &Client{
+// warnUserAgent logs once per process that a client sends the placeholder User-Agent
+var warnUserAgent sync.Once
+
+// NewClient creates a new EDGAR API client configured with the given options.
+// Without WithUserAgent or SEC_USER_AGENT (via WithEnv) a placeholder is sent, which the SEC throttles
+// or blocks; a warning is logged and CheckUserAgent returns ErrNoUserAgent.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
-		userAgent: userAgent,
+		userAgent:   userAgent,
+		baseURL:     baseURL,
+		archivesURL: archivesURL,
+		limiter:     defaultRateLimiter,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	if c.userAgent == userAgent {
+		warnUserAgent.Do(func() {
+			log.Printf("Warning: No User-Agent configured; set %s or use WithUserAgent, as the SEC throttles or blocks the placeholder", EnvUserAgent)
+		})
+	}
+
+	return c
 }
 
-// makeRequest is a helper function to make HTTP requests with proper headers and gzip handling
-func (c *Client) makeRequest(url string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+// CheckUserAgent returns ErrNoUserAgent if the client sends the placeholder User-Agent, i.e. neither
+// WithUserAgent nor SEC_USER_AGENT identified the caller as the SEC fair-access policy requires
+func (c *Client) CheckUserAgent() error {
+	if c.userAgent == userAgent {
+		return fmt.Errorf("set %s or use WithUserAgent, e.g. \"Sample Company admin@example.com\": %w", EnvUserAgent, ErrNoUserAgent)
+	}
+	return nil
+}
+
+// ArchivesURL returns the base URL of the archives host, e.g. for building URLs passed to OpenArchive
+func (c *Client) ArchivesURL() string {
+	return c.archivesURL
+}
+
+// makeRequest is a helper function to make HTTP requests with proper headers and gzip handling.
+// Responses are served from a bulk archive or the client's cache when one is configured.
+func (c *Client) makeRequest(ctx context.Context, url string) ([]byte, error) {
+	if body, ok, err := c.bulkRequest(url); ok {
+		return body, err
 	}
 
-	req.Header.Set("User-Agent", c.userAgent)
-	req.Header.Set("Accept-Encoding", "gzip, deflate")
-	req.Header.Set("Host", "data.sec.gov")
+	if c.cache != nil {
+		return c.cachedRequest(ctx, url)
+	}
+
+	body, _, err := c.fetch(ctx, url, nil)
+	return body, err
+}
 
-	resp, err := c.httpClient.Do(req)
+// fetch performs a GET request with the given extra headers and returns the decompressed body.
+// A 304 Not Modified response is returned with a nil body.
+func (c *Client) fetch(ctx context.Context, url string, header http.Header) ([]byte, *http.Response, error) {
+	resp, err := c.doRequest(ctx, url, header)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, nil, err
 	}
 	defer func() { _ = resp.Body.Close() }() // Ignoring close error
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp, nil
 	}
 
-	var reader io.Reader = resp.Body
-
-	// Check if response is gzip compressed
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzipReader, err := gzip.NewReader(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("error creating gzip reader: %w", err)
-		}
-		defer func() { _ = gzipReader.Close() }() // Ignoring close error
-		reader = gzipReader
+	reader, err := responseBody(resp)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer func() { _ = reader.Close() }() // Ignoring close error
 
 	body, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, nil, fmt.Errorf("error reading response body: %w", err)
 	}
 
-	return body, nil
+	return body, resp, nil
 }
 
-// CompanyFacts represents the company facts response
-type CompanyFacts struct {
-	// Add fields based on the API response structure
-	CIK    interface{}            `json:"cik"` // Can be string or number
-	Entity string                 `json:"entityName"`
-	Facts  map[string]interface{} `json:"facts"`
+// responseBody returns the response body, decompressing it if the response is gzip compressed.
+// Closing the returned reader closes the response body.
+func responseBody(resp *http.Response) (io.ReadCloser, error) {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return resp.Body, nil
+	}
+
+	gzipReader, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		_ = resp.Body.Close() // Ignoring close error, the body is unusable
+		return nil, fmt.Errorf("error creating gzip reader: %w", err)
+	}
+
+	return &gzipBody{Reader: gzipReader, body: resp.Body}, nil
 }
 
-// GetCIKString returns the CIK as a string
-func (cf *CompanyFacts) GetCIKString() string {
-	switch v := cf.CIK.(type) {
-	case string:
-		return v
-	case json.Number:
-		return string(v)
-	case float64:
-		return fmt.Sprintf("%.0f", v)
-	case int:
-		return fmt.Sprintf("%d", v)
-	default:
-		return fmt.Sprintf("%v", v)
+// gzipBody is a decompressing reader that also closes the underlying response body
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipBody) Close() error {
+	_ = g.Reader.Close() // Ignoring close error, the body close error is more useful
+	return g.body.Close()
+}
+
+// doRequest performs a GET request, retrying transient failures according to the client's retry policy.
+// On success the caller owns the returned response body.
+func (c *Client) doRequest(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	return c.doRequestWith(ctx, c.httpClient, url, header)
+}
+
+// doRequestWith performs a GET request like doRequest, sending it through hc
+func (c *Client) doRequestWith(ctx context.Context, hc *http.Client, url string, header http.Header) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doRequestOnce(ctx, hc, url, header)
+		if err == nil {
+			return resp, nil
+		}
+
+		delay, retry := c.retryPolicy.shouldRetry(ctx, attempt, err)
+		if !retry {
+			return nil, err
+		}
+
+		c.stats.retries.Add(1)
+		if c.retryPolicy.OnRetry != nil {
+			c.retryPolicy.OnRetry(RetryEvent{URL: url, Attempt: attempt, Err: err, Delay: delay})
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// doRequestOnce performs a single rate-limited GET request and converts non-200 responses into errors.
+// 304 Not Modified is passed through for conditional requests.
+func (c *Client) doRequestOnce(ctx context.Context, hc *http.Client, url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	for key, values := range header {
+		req.Header[key] = values
 	}
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("error waiting for rate limiter: %w", err)
+	}
+
+	c.stats.requests.Add(1)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotModified {
+		defer func() { _ = resp.Body.Close() }() // Ignoring close error
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			URL:        url,
+			Body:       string(body),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return resp, nil
+}
+
+// streamingClient returns the HTTP client used for downloads, whose bodies can take longer to read than
+// the overall request timeout allows. It has no overall timeout, so the context cancels a download; the
+// timeout still bounds the wait for the response headers unless a custom RoundTripper is configured.
+func (c *Client) streamingClient() *http.Client {
+	c.streamOnce.Do(func() {
+		timeout := c.httpClient.Timeout
+		if timeout == 0 {
+			c.streamClient = c.httpClient
+			return
+		}
+
+		hc := *c.httpClient
+		hc.Timeout = 0
+		transport, ok := hc.Transport.(*http.Transport)
+		if hc.Transport == nil {
+			transport, ok = http.DefaultTransport.(*http.Transport)
+		}
+		if ok && transport.ResponseHeaderTimeout == 0 {
+			transport = transport.Clone()
+			transport.ResponseHeaderTimeout = timeout
+			hc.Transport = transport
+		}
+		c.streamClient = &hc
+	})
+	return c.streamClient
 }
 
 // CompanySubmissions represents the company submissions response
@@ -128,15 +287,21 @@ type CompanySubmissions struct {
 		From string `json:"from"`
 		To   string `json:"to"`
 	} `json:"formerNames"`
-	Filings struct {
-		Recent map[string][]interface{} `json:"recent"`
-		Files  []struct {
-			Name        string `json:"name"`
-			FilingCount int    `json:"filingCount"`
-			FilingFrom  string `json:"filingFrom"`
-			FilingTo    string `json:"filingTo"`
-		} `json:"files"`
-	} `json:"filings"`
+	Filings SubmissionsFilings `json:"filings"`
+}
+
+// SubmissionsFilings holds the most recent filings and the pages listing older ones
+type SubmissionsFilings struct {
+	Recent map[string][]interface{} `json:"recent"` // Column arrays, roughly the last 1000 filings
+	Files  []SubmissionsFile        `json:"files"`
+}
+
+// SubmissionsFile describes a page of older filings, e.g. CIK0000320193-submissions-001.json
+type SubmissionsFile struct {
+	Name        string `json:"name"`
+	FilingCount int    `json:"filingCount"`
+	FilingFrom  string `json:"filingFrom"`
+	FilingTo    string `json:"filingTo"`
 }
 
 type Address struct {
@@ -148,33 +313,38 @@ type Address struct {
 	StateOrCountryDesc string `json:"stateOrCountryDescription"`
 }
 
-// Filing represents a single filing
+// Filing represents a single filing. JSON field names match the submissions payload.
 type Filing struct {
-	AccessionNumber string
-	FilingDate      string
-	ReportDate      string
-	Form            string
-	FileNumber      string
-	FilmNumber      string
-	Items           string
-	Size            string
-	IsXBRL          string
-	IsInlineXBRL    string
-	PrimaryDocument string
-	PrimaryDocDesc  string
-}
-
-// CashFlowMetrics represents the parsed cash flow metrics
+	AccessionNumber    string    `json:"accessionNumber"`
+	FilingDate         Date      `json:"filingDate"`
+	ReportDate         Date      `json:"reportDate"` // Period end date, zero for forms without one
+	AcceptanceDateTime time.Time `json:"acceptanceDateTime"`
+	Act                string    `json:"act"` // Securities act, e.g. "34"
+	Form               string    `json:"form"`
+	FileNumber         string    `json:"fileNumber"`
+	FilmNumber         string    `json:"filmNumber"`
+	Items              []string  `json:"items"` // 8-K item codes, e.g. "2.02"
+	CoreType           string    `json:"core_type"`
+	Size               int64     `json:"size"` // Bytes
+	IsXBRL             bool      `json:"isXBRL"`
+	IsInlineXBRL       bool      `json:"isInlineXBRL"`
+	PrimaryDocument    string    `json:"primaryDocument"`
+	PrimaryDocDesc     string    `json:"primaryDocDescription"`
+}
+
+// CashFlowMetrics represents the parsed cash flow metrics. Values that could not be found are nil.
 type CashFlowMetrics struct {
-	CompanyName                    string  `json:"companyName"`
-	CIK                            string  `json:"cik"`
-	FilingDate                     string  `json:"filingDate"`
-	ReportDate                     string  `json:"reportDate"`
-	NetCashFromOperatingActivities float64 `json:"netCashFromOperatingActivities"`
-	CapitalExpenditures            float64 `json:"capitalExpenditures"`
-	FreeCashFlow                   float64 `json:"freeCashFlow"`
-	Form                           string  `json:"form"`
-	AccessionNumber                string  `json:"accessionNumber"`
+	CompanyName                    string   `json:"companyName"`
+	CIK                            string   `json:"cik"`
+	FilingDate                     string   `json:"filingDate"`
+	ReportDate                     string   `json:"reportDate"`
+	NetCashFromOperatingActivities *float64 `json:"netCashFromOperatingActivities"`
+	CapitalExpenditures            *float64 `json:"capitalExpenditures"`
+	FreeCashFlow                   *float64 `json:"freeCashFlow"` // Requires operating cash flow; missing capex counts as zero, see IncompleteTotals
+	Form                           string   `json:"form"`
+	AccessionNumber                string   `json:"accessionNumber"`
+	Currency                       string   `json:"currency"` // Reporting currency of the company facts, e.g. "USD"
+	MetricStatus
 }
 
 // QuarterlyCashFlowAnalysis represents cash flow metrics for multiple quarters
@@ -184,21 +354,23 @@ type QuarterlyCashFlowAnalysis struct {
 	Quarters    []CashFlowMetrics `json:"quarters"`
 }
 
-// EBITDAMetrics represents the calculated EBITDA metrics
+// EBITDAMetrics represents the calculated EBITDA metrics. Values that could not be found are nil.
 type EBITDAMetrics struct {
-	CompanyName                 string  `json:"companyName"`
-	CIK                         string  `json:"cik"`
-	FilingDate                  string  `json:"filingDate"`
-	ReportDate                  string  `json:"reportDate"`
-	Form                        string  `json:"form"`
-	AccessionNumber             string  `json:"accessionNumber"`
-	Revenue                     float64 `json:"revenue"`
-	NetIncome                   float64 `json:"netIncome"`
-	InterestExpense             float64 `json:"interestExpense"`
-	IncomeTaxExpense            float64 `json:"incomeTaxExpense"`
-	DepreciationAndAmortization float64 `json:"depreciationAndAmortization"`
-	EBITDA                      float64 `json:"ebitda"`
-	EBITDAMargin                float64 `json:"ebitdaMargin"` // EBITDA / Revenue as percentage
+	CompanyName                 string   `json:"companyName"`
+	CIK                         string   `json:"cik"`
+	FilingDate                  string   `json:"filingDate"`
+	ReportDate                  string   `json:"reportDate"`
+	Form                        string   `json:"form"`
+	AccessionNumber             string   `json:"accessionNumber"`
+	Currency                    string   `json:"currency"` // Reporting currency of the company facts, e.g. "USD"
+	Revenue                     *float64 `json:"revenue"`
+	NetIncome                   *float64 `json:"netIncome"`
+	InterestExpense             *float64 `json:"interestExpense"`
+	IncomeTaxExpense            *float64 `json:"incomeTaxExpense"`
+	DepreciationAndAmortization *float64 `json:"depreciationAndAmortization"`
+	EBITDA                      *float64 `json:"ebitda"`       // Requires net income; missing interest, tax or D&A count as zero, see IncompleteTotals
+	EBITDAMargin                *float64 `json:"ebitdaMargin"` // EBITDA / Revenue as percentage
+	MetricStatus
 }
 
 // QuarterlyEBITDAAnalysis represents EBITDA metrics for multiple quarters
@@ -209,67 +381,52 @@ type QuarterlyEBITDAAnalysis struct {
 }
 
 // GetCompanyFacts retrieves company facts for a given CIK
-func (c *Client) GetCompanyFacts(cik string) (*CompanyFacts, error) {
-	url := fmt.Sprintf("%s/api/xbrl/companyfacts/CIK%s.json", baseURL, cik)
+func (c *Client) GetCompanyFacts(ctx context.Context, cik string) (*CompanyFacts, error) {
+	url := fmt.Sprintf("%s%sCIK%s.json", c.baseURL, PathCompanyFacts, cik)
 
-	body, err := c.makeRequest(url)
+	body, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
 	var facts CompanyFacts
 	if err := json.Unmarshal(body, &facts); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+		return nil, &DecodeError{URL: url, Err: err}
 	}
 
 	return &facts, nil
 }
 
 // GetCompanySubmissions retrieves company submissions for a given CIK
-func (c *Client) GetCompanySubmissions(cik string) (*CompanySubmissions, error) {
-	url := fmt.Sprintf("%s/submissions/CIK%s.json", baseURL, cik)
+func (c *Client) GetCompanySubmissions(ctx context.Context, cik string) (*CompanySubmissions, error) {
+	url := fmt.Sprintf("%s%sCIK%s.json", c.baseURL, PathSubmissions, cik)
 
-	body, err := c.makeRequest(url)
+	body, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
 	var submissions CompanySubmissions
 	if err := json.Unmarshal(body, &submissions); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+		return nil, &DecodeError{URL: url, Err: err}
 	}
+	c.rememberSIC(cik, submissions.SIC)
 
 	return &submissions, nil
 }
 
 // GetMostRecent10Q finds the most recent 10-Q filing from company submissions
-func (c *Client) GetMostRecent10Q(cik string) (*Filing, error) {
-	submissions, err := c.GetCompanySubmissions(cik)
+func (c *Client) GetMostRecent10Q(ctx context.Context, cik string) (*Filing, error) {
+	filings, err := c.ListFilings(ctx, cik, FilingQuery{Forms: []string{"10-Q"}, Limit: 1})
 	if err != nil {
-		return nil, fmt.Errorf("error getting company submissions: %w", err)
-	}
-
-	// Parse recent filings
-	filings := c.parseFilings(submissions.Filings.Recent)
-
-	// Filter for 10-Q filings and sort by filing date (most recent first)
-	var tenQFilings []Filing
-	for _, filing := range filings {
-		if filing.Form == "10-Q" {
-			tenQFilings = append(tenQFilings, filing)
-		}
+		return nil, err
 	}
 
-	if len(tenQFilings) == 0 {
-		return nil, fmt.Errorf("no 10-Q filings found for CIK %s", cik)
+	if len(filings) == 0 {
+		return nil, fmt.Errorf("no 10-Q filings found for CIK %s: %w", cik, ErrNoFilings)
 	}
 
-	// Sort by filing date (most recent first)
-	sort.Slice(tenQFilings, func(i, j int) bool {
-		return tenQFilings[i].FilingDate > tenQFilings[j].FilingDate
-	})
-
-	return &tenQFilings[0], nil
+	return &filings[0], nil
 }
 
 // parseFilings converts the submissions recent filings map to Filing structs
@@ -281,21 +438,33 @@ func (c *Client) parseFilings(recent map[string][]interface{}) []Filing {
 		return filings
 	}
 
+	// column returns the i-th value of a column, or nil if the column is missing or short
+	column := func(name string, i int) interface{} {
+		values := recent[name]
+		if i >= len(values) {
+			return nil
+		}
+		return values[i]
+	}
+
 	count := len(recent["accessionNumber"])
 	for i := 0; i < count; i++ {
 		filing := Filing{
-			AccessionNumber: c.toString(recent["accessionNumber"][i]),
-			FilingDate:      c.toString(recent["filingDate"][i]),
-			ReportDate:      c.toString(recent["reportDate"][i]),
-			Form:            c.toString(recent["form"][i]),
-			FileNumber:      c.toString(recent["fileNumber"][i]),
-			FilmNumber:      c.toString(recent["filmNumber"][i]),
-			Items:           c.toString(recent["items"][i]),
-			Size:            c.toString(recent["size"][i]),
-			IsXBRL:          c.toString(recent["isXBRL"][i]),
-			IsInlineXBRL:    c.toString(recent["isInlineXBRL"][i]),
-			PrimaryDocument: c.toString(recent["primaryDocument"][i]),
-			PrimaryDocDesc:  c.toString(recent["primaryDocDescription"][i]),
+			AccessionNumber:    c.toString(column("accessionNumber", i)),
+			FilingDate:         c.toDate(column("filingDate", i)),
+			ReportDate:         c.toDate(column("reportDate", i)),
+			AcceptanceDateTime: c.toTime(column("acceptanceDateTime", i)),
+			Act:                c.toString(column("act", i)),
+			Form:               c.toString(column("form", i)),
+			FileNumber:         c.toString(column("fileNumber", i)),
+			FilmNumber:         c.toString(column("filmNumber", i)),
+			Items:              c.toItems(column("items", i)),
+			CoreType:           c.toString(column("core_type", i)),
+			Size:               c.toInt64(column("size", i)),
+			IsXBRL:             c.toBool(column("isXBRL", i)),
+			IsInlineXBRL:       c.toBool(column("isInlineXBRL", i)),
+			PrimaryDocument:    c.toString(column("primaryDocument", i)),
+			PrimaryDocDesc:     c.toString(column("primaryDocDescription", i)),
 		}
 		filings = append(filings, filing)
 	}
@@ -325,52 +494,116 @@ func (c *Client) toString(v interface{}) string {
 	}
 }
 
-// GetMostRecent4TenQs finds the 4 most recent 10-Q filings from company submissions
-func (c *Client) GetMostRecent4TenQs(cik string) ([]Filing, error) {
-	submissions, err := c.GetCompanySubmissions(cik)
+// toDate converts a "2006-01-02" value to a Date, yielding the zero Date for empty or invalid values
+func (c *Client) toDate(v interface{}) Date {
+	d, err := ParseDate(c.toString(v))
 	if err != nil {
-		return nil, fmt.Errorf("error getting company submissions: %w", err)
+		return Date{}
 	}
+	return d
+}
 
-	// Parse recent filings
-	filings := c.parseFilings(submissions.Filings.Recent)
+// toTime converts an RFC 3339 timestamp such as "2024-02-01T18:03:43.000Z" to a time.Time
+func (c *Client) toTime(v interface{}) time.Time {
+	t, err := time.Parse(time.RFC3339, c.toString(v))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
 
-	// Filter for 10-Q filings and sort by filing date (most recent first)
-	var tenQFilings []Filing
-	for _, filing := range filings {
-		if filing.Form == "10-Q" {
-			tenQFilings = append(tenQFilings, filing)
+// toInt64 converts a number or numeric string to int64, yielding 0 for other values
+func (c *Client) toInt64(v interface{}) int64 {
+	switch val := v.(type) {
+	case float64:
+		return int64(val)
+	case int:
+		return int64(val)
+	case int64:
+		return val
+	case string:
+		n, _ := strconv.ParseInt(val, 10, 64) // Invalid sizes are reported as 0
+		return n
+	default:
+		return 0
+	}
+}
+
+// toBool converts the submissions 0/1 flags to bool
+func (c *Client) toBool(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case int:
+		return val != 0
+	case string:
+		return val == "1" || strings.EqualFold(val, "true")
+	default:
+		return false
+	}
+}
+
+// toItems splits a comma-separated item list such as "2.02,9.01", yielding nil when empty
+func (c *Client) toItems(v interface{}) []string {
+	var items []string
+	for _, item := range strings.Split(c.toString(v), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
 	}
+	return items
+}
 
-	if len(tenQFilings) == 0 {
-		return nil, fmt.Errorf("no 10-Q filings found for CIK %s", cik)
+// GetMostRecent4TenQs finds the 4 most recent 10-Q filings from company submissions
+func (c *Client) GetMostRecent4TenQs(ctx context.Context, cik string) ([]Filing, error) {
+	filings, err := c.ListFilings(ctx, cik, FilingQuery{Forms: []string{"10-Q"}, Limit: 4})
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort by filing date (most recent first)
-	sort.Slice(tenQFilings, func(i, j int) bool {
-		return tenQFilings[i].FilingDate > tenQFilings[j].FilingDate
-	})
+	if len(filings) == 0 {
+		return nil, fmt.Errorf("no 10-Q filings found for CIK %s: %w", cik, ErrNoFilings)
+	}
+
+	return filings, nil
+}
 
-	// Return up to 4 most recent filings
-	count := len(tenQFilings)
-	if count > 4 {
-		count = 4
+// QuarterlyForms are the forms reporting a fiscal quarter: 10-Qs for the first three quarters, and the 10-K
+// for the fourth, whose values are derived as 12M − 9M
+var QuarterlyForms = []string{"10-Q", "10-K"}
+
+// GetMostRecentQuarterlyFilings finds the filings reporting the most recent fiscal quarters (10-Q or 10-K), newest first
+func (c *Client) GetMostRecentQuarterlyFilings(ctx context.Context, cik string, quarters int) ([]Filing, error) {
+	if quarters < 1 {
+		return nil, fmt.Errorf("quarters must be at least 1, got %d", quarters)
 	}
 
-	return tenQFilings[:count], nil
+	filings, err := c.ListFilings(ctx, cik, FilingQuery{Forms: QuarterlyForms, Limit: quarters})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filings) == 0 {
+		return nil, fmt.Errorf("no 10-Q or 10-K filings found for CIK %s: %w", cik, ErrNoFilings)
+	}
+
+	return filings, nil
 }
 
-// GetQuarterlyCashFlowAnalysis retrieves cash flow metrics for the 4 most recent 10-Q filings
-func (c *Client) GetQuarterlyCashFlowAnalysis(cik string) (*QuarterlyCashFlowAnalysis, error) {
-	// Get the 4 most recent 10-Q filings
-	filings, err := c.GetMostRecent4TenQs(cik)
+// GetQuarterlyCashFlowAnalysis retrieves cash flow metrics for the 4 most recent fiscal quarters, one per 10-Q
+// or 10-K filing. Values are for the 3 months ending on each report date, derived from year-to-date facts
+// where needed, so the fourth quarter of a 10-K is its 12-month value minus the 9-month value.
+func (c *Client) GetQuarterlyCashFlowAnalysis(ctx context.Context, cik string) (*QuarterlyCashFlowAnalysis, error) {
+	// Get the filings of the 4 most recent quarters
+	filings, err := c.GetMostRecentQuarterlyFilings(ctx, cik, 4)
 	if err != nil {
-		return nil, fmt.Errorf("error getting recent 10-Q filings: %w", err)
+		return nil, fmt.Errorf("error getting recent quarterly filings: %w", err)
 	}
 
 	// Get company facts once (we'll reuse this for all quarters)
-	facts, err := c.GetCompanyFacts(cik)
+	facts, err := c.GetCompanyFacts(ctx, cik)
 	if err != nil {
 		return nil, fmt.Errorf("error getting company facts: %w", err)
 	}
@@ -380,10 +613,14 @@ func (c *Client) GetQuarterlyCashFlowAnalysis(cik string) (*QuarterlyCashFlowAna
 		CIK:         facts.GetCIKString(),
 		Quarters:    make([]CashFlowMetrics, 0, len(filings)),
 	}
+	concepts := c.companyConcepts(ctx, cik)
 
 	// Parse cash flow metrics for each filing
 	for _, filing := range filings {
-		metrics, err := c.ParseCashFlowMetricsFromFacts(facts, &filing)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		metrics, err := c.parseCashFlowMetrics(facts, &filing, DurationQuarter, concepts)
 		if err != nil {
 			log.Printf("Warning: Could not parse cash flow metrics for filing %s: %v", filing.AccessionNumber, err)
 			continue
@@ -392,344 +629,286 @@ func (c *Client) GetQuarterlyCashFlowAnalysis(cik string) (*QuarterlyCashFlowAna
 	}
 
 	if len(analysis.Quarters) == 0 {
-		return nil, fmt.Errorf("no cash flow metrics could be extracted from any 10-Q filings")
+		return nil, fmt.Errorf("no cash flow metrics could be extracted from any quarterly filings")
 	}
 
 	return analysis, nil
 }
 
-// ParseCashFlowMetrics extracts cash flow metrics from a 10-Q filing
-func (c *Client) ParseCashFlowMetrics(cik string, filing *Filing) (*CashFlowMetrics, error) {
+// ParseCashFlowMetrics extracts cash flow metrics from a 10-Q filing, applying the concept map's overrides
+// for the company's SIC code
+func (c *Client) ParseCashFlowMetrics(ctx context.Context, cik string, filing *Filing) (*CashFlowMetrics, error) {
 	// Get company facts which contain the financial data
-	facts, err := c.GetCompanyFacts(cik)
+	facts, err := c.GetCompanyFacts(ctx, cik)
 	if err != nil {
 		return nil, fmt.Errorf("error getting company facts: %w", err)
 	}
 
-	metrics := &CashFlowMetrics{
-		CompanyName:     facts.Entity,
-		CIK:             facts.GetCIKString(),
-		FilingDate:      filing.FilingDate,
-		ReportDate:      filing.ReportDate,
-		Form:            filing.Form,
-		AccessionNumber: filing.AccessionNumber,
-	}
-
-	// Extract cash flow metrics from facts
-	if err := c.extractCashFlowData(facts, metrics, filing.ReportDate); err != nil {
-		return nil, fmt.Errorf("error extracting cash flow data: %w", err)
-	}
-
-	// Calculate free cash flow
-	metrics.FreeCashFlow = metrics.NetCashFromOperatingActivities - metrics.CapitalExpenditures
-
-	return metrics, nil
+	return c.parseCashFlowMetrics(facts, filing, DurationQuarter, c.companyConcepts(ctx, cik))
 }
 
-// extractCashFlowData extracts specific cash flow values from company facts
-func (c *Client) extractCashFlowData(facts *CompanyFacts, metrics *CashFlowMetrics, reportDate string) error {
+// extractCashFlowData extracts the cash flows for the period selected by sel from company facts
+func (c *Client) extractCashFlowData(facts *CompanyFacts, concepts map[string][]ConceptCandidate, metrics *CashFlowMetrics, sel FactSelector) error {
 	// Navigate through the facts structure to find cash flow data
-	factsMap := facts.Facts
-	if factsMap == nil {
+	if facts.Facts == nil {
 		return fmt.Errorf("facts data is nil")
 	}
 
-	// Look for US-GAAP taxonomy
-	usGaap, ok := factsMap["us-gaap"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("us-gaap taxonomy not found")
+	if err := checkTaxonomies(facts, concepts); err != nil {
+		return err
 	}
 
 	// Extract Net Cash from Operating Activities
-	if err := c.extractMetric(usGaap, []string{
-		"NetCashProvidedByUsedInOperatingActivities",
-		"NetCashFromOperatingActivities",
-		"CashProvidedByUsedInOperatingActivities",
-	}, &metrics.NetCashFromOperatingActivities, reportDate); err != nil {
+	if err := c.extractComponent(facts, concepts, sel, MetricNetCashFromOperatingActivities, &metrics.NetCashFromOperatingActivities, &metrics.MetricStatus); err != nil {
 		log.Printf("Warning: Could not extract operating cash flow: %v", err)
 	}
 
 	// Extract Capital Expenditures
-	if err := c.extractMetric(usGaap, []string{
-		"PaymentsToAcquirePropertyPlantAndEquipment",
-		"CapitalExpenditures",
-		"PaymentsForPropertyPlantAndEquipment",
-		"PaymentsToAcquireProductiveAssets",
-	}, &metrics.CapitalExpenditures, reportDate); err != nil {
+	if err := c.extractComponent(facts, concepts, sel, MetricCapitalExpenditures, &metrics.CapitalExpenditures, &metrics.MetricStatus); err != nil {
 		log.Printf("Warning: Could not extract capital expenditures: %v", err)
 	}
 
 	return nil
 }
 
-// extractMetric tries to extract a metric value using multiple possible tag names
-func (c *Client) extractMetric(usGaap map[string]interface{}, tagNames []string, result *float64, reportDate string) error {
-	for _, tagName := range tagNames {
-		if concept, ok := usGaap[tagName].(map[string]interface{}); ok {
-			if units, ok := concept["units"].(map[string]interface{}); ok {
-				// Try USD first, then other units
-				for unitType, unitData := range units {
-					if strings.Contains(strings.ToLower(unitType), "usd") {
-						if dataArray, ok := unitData.([]interface{}); ok {
-							// Find the most recent value for the report date
-							value := c.findValueForDate(dataArray, reportDate)
-							if value != 0 {
-								*result = value
-								return nil
-							}
-						}
-					}
-				}
-			}
-		}
+// extractComponent extracts the metric name (also the JSON field of the component) into *value, recording
+// in status whether the component was missing or derived, and where it came from
+func (c *Client) extractComponent(facts *CompanyFacts, concepts map[string][]ConceptCandidate, sel FactSelector, name string, value **float64, status *MetricStatus) error {
+	source, err := c.extractMetric(facts, concepts[name], sel)
+	if err != nil {
+		status.addMissing(name)
+		return err
+	}
+
+	*value = Float(source.Value)
+	status.addSource(name, source)
+	if source.Derivation != "" {
+		status.addDerivation(name, source.Derivation)
 	}
-	return fmt.Errorf("metric not found with any of the provided tag names: %v", tagNames)
-}
-
-// findValueForDate finds the value closest to the given report date
-func (c *Client) findValueForDate(dataArray []interface{}, targetDate string) float64 {
-	var bestValue float64
-	var bestDate string
-	var bestScore int // Higher score = better match
-
-	for _, item := range dataArray {
-		if dataPoint, ok := item.(map[string]interface{}); ok {
-			if date, ok := dataPoint["end"].(string); ok {
-				if form, ok := dataPoint["form"].(string); ok {
-					// Calculate match score
-					score := 0
-
-					// Prefer exact date matches
-					if date == targetDate {
-						score += 100
-					}
-
-					// Prefer 10-Q forms for quarterly analysis
-					switch form {
-					case "10-Q":
-						score += 50
-					case "10-K":
-						score += 10 // Lower priority for annual forms
-					}
-
-					// Prefer more recent dates if no exact match
-					if date <= targetDate && date > bestDate {
-						score += 25
-					}
-
-					// Only update if this is a better match
-					if score > bestScore || (score == bestScore && date > bestDate) {
-						if val, ok := dataPoint["val"].(float64); ok {
-							bestValue = val
-							bestDate = date
-							bestScore = score
-						} else if valStr, ok := dataPoint["val"].(string); ok {
-							if val, err := strconv.ParseFloat(valStr, 64); err == nil {
-								bestValue = val
-								bestDate = date
-								bestScore = score
-							}
-						}
-					}
-				}
+	return nil
+}
+
+// extractMetric returns the fact of the first candidate concept matching sel, adjusted by the candidate's
+// sign rule. A quarter that was only reported year-to-date is derived from the year-to-date facts, and
+// the derivation is recorded in its provenance; per-share and share-count quarters must be reported.
+func (c *Client) extractMetric(facts *CompanyFacts, candidates []ConceptCandidate, sel FactSelector) (Provenance, error) {
+	currency := facts.ReportingCurrency()
+	for _, candidate := range candidates {
+		concept, ok := facts.Concept(candidate.Taxonomy, candidate.Tag)
+		if !ok || concept == nil {
+			continue
+		}
+
+		// Try the candidate's unit, or the plain reporting currency first and then other units in it
+		for _, unit := range candidate.units(concept, currency) {
+			unitFacts := concept.Facts(unit)
+
+			var fact Fact
+			var derivation string
+			if sel.Duration == DurationQuarter && additive(unit) {
+				fact, derivation, ok = discreteQuarter(unitFacts, sel)
+			} else {
+				fact, ok = sel.Select(unitFacts)
+			}
+			if !ok {
+				continue
 			}
+
+			source := newProvenance(candidate.Taxonomy, candidate.Tag, unit, fact, derivation)
+			source.Value = candidate.apply(fact.Val)
+			source.Sign = candidate.Sign
+			source.FromOtherFiling = sel.FromOtherFiling(fact)
+			source.Override = candidate.override
+			return source, nil
 		}
 	}
-
-	return bestValue
+	return Provenance{}, fmt.Errorf("metric not found with any of the candidate concepts: %v", candidates)
 }
 
-// ParseCashFlowMetricsFromFacts extracts cash flow metrics using pre-fetched company facts
+// ParseCashFlowMetricsFromFacts extracts the filing's quarterly cash flow metrics using pre-fetched company facts
 func (c *Client) ParseCashFlowMetricsFromFacts(facts *CompanyFacts, filing *Filing) (*CashFlowMetrics, error) {
+	return c.parseCashFlowMetrics(facts, filing, DurationQuarter, c.conceptsFor(facts.GetCIKString(), ""))
+}
+
+// parseCashFlowMetrics extracts cash flow metrics for the period of the given duration ending on the filing's
+// report date, reading each metric from its candidates in concepts
+func (c *Client) parseCashFlowMetrics(facts *CompanyFacts, filing *Filing, duration PeriodDuration, concepts map[string][]ConceptCandidate) (*CashFlowMetrics, error) {
 	metrics := &CashFlowMetrics{
 		CompanyName:     facts.Entity,
 		CIK:             facts.GetCIKString(),
-		FilingDate:      filing.FilingDate,
-		ReportDate:      filing.ReportDate,
+		FilingDate:      filing.FilingDate.String(),
+		ReportDate:      filing.ReportDate.String(),
 		Form:            filing.Form,
 		AccessionNumber: filing.AccessionNumber,
+		Currency:        facts.ReportingCurrency(),
+	}
+
+	if filing.ReportDate.IsZero() {
+		return nil, fmt.Errorf("filing %s has no report date", filing.AccessionNumber)
 	}
 
-	// Extract cash flow metrics from facts
-	if err := c.extractCashFlowData(facts, metrics, filing.ReportDate); err != nil {
+	// Extract cash flow metrics for the filing's period from facts
+	if err := c.extractCashFlowData(facts, concepts, metrics, c.forFiling(filing, duration)); err != nil {
 		return nil, fmt.Errorf("error extracting cash flow data: %w", err)
 	}
 
 	// Calculate free cash flow
-	metrics.FreeCashFlow = metrics.NetCashFromOperatingActivities - metrics.CapitalExpenditures
+	metrics.calculate()
 
 	return metrics, nil
 }
 
-// CompanyConcept represents a specific concept for a company
-type CompanyConcept struct {
-	CIK      string `json:"cik"`
-	Taxonomy string `json:"taxonomy"`
-	Tag      string `json:"tag"`
-	Label    string `json:"label"`
-	Units    map[string][]struct {
-		Form  string  `json:"form"`
-		Date  string  `json:"date"`
-		Value float64 `json:"val"`
-	} `json:"units"`
-}
-
-// GetCompanyConcept retrieves a specific concept for a company
-func (c *Client) GetCompanyConcept(cik, taxonomy, tag string) (*CompanyConcept, error) {
-	url := fmt.Sprintf("%s/api/xbrl/companyconcept/CIK%s/%s/%s.json", baseURL, cik, taxonomy, tag)
+// GetCompanyConcept retrieves a single concept for a company without downloading all of its facts
+func (c *Client) GetCompanyConcept(ctx context.Context, cik, taxonomy, tag string) (*CompanyConcept, error) {
+	url := fmt.Sprintf("%s%sCIK%s/%s/%s.json", c.baseURL, PathCompanyConcept, cik, taxonomy, tag)
 
-	body, err := c.makeRequest(url)
+	body, err := c.makeRequest(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
 	var concept CompanyConcept
 	if err := json.Unmarshal(body, &concept); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+		return nil, &DecodeError{URL: url, Err: err}
 	}
 
 	return &concept, nil
 }
 
-// ParseEBITDAMetrics extracts EBITDA components from a 10-Q filing
-func (c *Client) ParseEBITDAMetrics(cik string, filing *Filing) (*EBITDAMetrics, error) {
+// ParseEBITDAMetrics extracts EBITDA components from a 10-Q filing, applying the concept map's overrides
+// for the company's SIC code
+func (c *Client) ParseEBITDAMetrics(ctx context.Context, cik string, filing *Filing) (*EBITDAMetrics, error) {
 	// Get company facts which contain the financial data
-	facts, err := c.GetCompanyFacts(cik)
+	facts, err := c.GetCompanyFacts(ctx, cik)
 	if err != nil {
 		return nil, fmt.Errorf("error getting company facts: %w", err)
 	}
 
-	return c.ParseEBITDAMetricsFromFacts(facts, filing)
+	return c.parseEBITDAMetrics(facts, filing, DurationQuarter, c.companyConcepts(ctx, cik))
 }
 
-// ParseEBITDAMetricsFromFacts extracts EBITDA components using pre-fetched company facts
+// ParseEBITDAMetricsFromFacts extracts the filing's quarterly EBITDA components using pre-fetched company facts
 func (c *Client) ParseEBITDAMetricsFromFacts(facts *CompanyFacts, filing *Filing) (*EBITDAMetrics, error) {
+	return c.parseEBITDAMetrics(facts, filing, DurationQuarter, c.conceptsFor(facts.GetCIKString(), ""))
+}
+
+// parseEBITDAMetrics extracts EBITDA metrics for the period of the given duration ending on the filing's
+// report date, reading each metric from its candidates in concepts
+func (c *Client) parseEBITDAMetrics(facts *CompanyFacts, filing *Filing, duration PeriodDuration, concepts map[string][]ConceptCandidate) (*EBITDAMetrics, error) {
 	metrics := &EBITDAMetrics{
 		CompanyName:     facts.Entity,
 		CIK:             facts.GetCIKString(),
-		FilingDate:      filing.FilingDate,
-		ReportDate:      filing.ReportDate,
+		FilingDate:      filing.FilingDate.String(),
+		ReportDate:      filing.ReportDate.String(),
 		Form:            filing.Form,
 		AccessionNumber: filing.AccessionNumber,
+		Currency:        facts.ReportingCurrency(),
 	}
 
-	// Extract EBITDA components from facts
-	if err := c.extractEBITDAData(facts, metrics, filing.ReportDate); err != nil {
-		return nil, fmt.Errorf("error extracting EBITDA data: %w", err)
+	if filing.ReportDate.IsZero() {
+		return nil, fmt.Errorf("filing %s has no report date", filing.AccessionNumber)
 	}
 
-	// Calculate EBITDA
-	metrics.EBITDA = metrics.NetIncome + metrics.InterestExpense + metrics.IncomeTaxExpense + metrics.DepreciationAndAmortization
-
-	// Calculate EBITDA Margin (as percentage)
-	if metrics.Revenue != 0 {
-		metrics.EBITDAMargin = (metrics.EBITDA / metrics.Revenue) * 100
-	} else {
-		log.Printf("Warning: Revenue is zero, cannot calculate EBITDA margin")
-		metrics.EBITDAMargin = 0
+	// Extract EBITDA components for the filing's period from facts
+	if err := c.extractEBITDAData(facts, concepts, metrics, c.forFiling(filing, duration)); err != nil {
+		return nil, fmt.Errorf("error extracting EBITDA data: %w", err)
 	}
 
+	// Calculate EBITDA and EBITDA margin
+	metrics.calculate()
+
 	return metrics, nil
 }
 
-// extractEBITDAData extracts specific EBITDA components from company facts
-func (c *Client) extractEBITDAData(facts *CompanyFacts, metrics *EBITDAMetrics, reportDate string) error {
+// extractEBITDAData extracts the EBITDA components for the period selected by sel from company facts
+func (c *Client) extractEBITDAData(facts *CompanyFacts, concepts map[string][]ConceptCandidate, metrics *EBITDAMetrics, sel FactSelector) error {
 	// Navigate through the facts structure to find financial data
-	factsMap := facts.Facts
-	if factsMap == nil {
+	if facts.Facts == nil {
 		return fmt.Errorf("facts data is nil")
 	}
 
-	// Look for US-GAAP taxonomy
-	usGaap, ok := factsMap["us-gaap"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("us-gaap taxonomy not found")
+	if err := checkTaxonomies(facts, concepts); err != nil {
+		return err
 	}
 
 	// Extract Revenue
-	if err := c.extractMetric(usGaap, []string{
-		"Revenues",
-		"RevenueFromContractWithCustomerExcludingAssessedTax",
-		"SalesRevenueNet",
-		"RevenueFromContractWithCustomerIncludingAssessedTax",
-		"Revenue",
-		"SalesRevenueGoodsNet",
-		"RevenuesNetOfInterestExpense",
-	}, &metrics.Revenue, reportDate); err != nil {
+	if err := c.extractComponent(facts, concepts, sel, MetricRevenue, &metrics.Revenue, &metrics.MetricStatus); err != nil {
 		log.Printf("Warning: Could not extract revenue: %v", err)
 	}
 
 	// Extract Net Income
-	if err := c.extractMetric(usGaap, []string{
-		"NetIncomeLoss",
-		"ProfitLoss",
-		"NetIncomeLossAvailableToCommonStockholdersBasic",
-		"IncomeLossFromContinuingOperations",
-	}, &metrics.NetIncome, reportDate); err != nil {
+	if err := c.extractComponent(facts, concepts, sel, MetricNetIncome, &metrics.NetIncome, &metrics.MetricStatus); err != nil {
 		log.Printf("Warning: Could not extract net income: %v", err)
 	}
 
 	// Extract Interest Expense
-	if err := c.extractMetric(usGaap, []string{
-		"InterestExpense",
-		"InterestExpenseDebt",
-		"InterestAndDebtExpense",
-		"InterestExpenseNet",
-	}, &metrics.InterestExpense, reportDate); err != nil {
+	if err := c.extractComponent(facts, concepts, sel, MetricInterestExpense, &metrics.InterestExpense, &metrics.MetricStatus); err != nil {
 		log.Printf("Warning: Could not extract interest expense: %v", err)
 	}
 
 	// Extract Income Tax Expense
-	if err := c.extractMetric(usGaap, []string{
-		"IncomeTaxExpenseBenefit",
-		"ProvisionForIncomeTaxes",
-		"IncomeTaxesPaid",
-		"CurrentIncomeTaxExpenseBenefit",
-	}, &metrics.IncomeTaxExpense, reportDate); err != nil {
+	if err := c.extractComponent(facts, concepts, sel, MetricIncomeTaxExpense, &metrics.IncomeTaxExpense, &metrics.MetricStatus); err != nil {
 		log.Printf("Warning: Could not extract income tax expense: %v", err)
 	}
 
 	// Extract Depreciation and Amortization
-	// This is often found in cash flow statement or as a combined figure
-	if err := c.extractMetric(usGaap, []string{
-		"DepreciationDepletionAndAmortization",
-		"Depreciation",
-		"DepreciationAndAmortization",
-		"AmortizationOfIntangibleAssets",
-		"DepreciationAmortizationAndAccretionNet",
-	}, &metrics.DepreciationAndAmortization, reportDate); err != nil {
-		log.Printf("Warning: Could not extract depreciation and amortization: %v", err)
-
-		// Try to get separate depreciation and amortization figures
-		var depreciation, amortization float64
-		if err1 := c.extractMetric(usGaap, []string{
-			"Depreciation",
-			"DepreciationNonproduction",
-		}, &depreciation, reportDate); err1 == nil {
-			metrics.DepreciationAndAmortization += depreciation
+	c.extractDepreciationAndAmortization(facts, concepts, sel, &metrics.DepreciationAndAmortization, &metrics.MetricStatus)
+
+	return nil
+}
+
+// extractDepreciationAndAmortization extracts the combined D&A figure, often found in the cash flow statement,
+// into *value. Without one, it sums the separate depreciation and amortization figures that were reported.
+func (c *Client) extractDepreciationAndAmortization(facts *CompanyFacts, concepts map[string][]ConceptCandidate, sel FactSelector, value **float64, status *MetricStatus) {
+	source, err := c.extractMetric(facts, concepts[MetricDepreciationAndAmortization], sel)
+	if err == nil {
+		*value = Float(source.Value)
+		status.addSource(MetricDepreciationAndAmortization, source)
+		if source.Derivation != "" {
+			status.addDerivation(MetricDepreciationAndAmortization, source.Derivation)
 		}
+		return
+	}
+	log.Printf("Warning: Could not extract depreciation and amortization: %v", err)
 
-		if err2 := c.extractMetric(usGaap, []string{
-			"AmortizationOfIntangibleAssets",
-			"Amortization",
-		}, &amortization, reportDate); err2 == nil {
-			metrics.DepreciationAndAmortization += amortization
+	// Try to get separate depreciation and amortization figures
+	var total float64
+	var found bool
+	var derivations []string
+	for _, part := range []string{MetricDepreciation, MetricAmortization} {
+		source, err := c.extractMetric(facts, concepts[part], sel)
+		if err != nil {
+			continue
+		}
+		total += source.Value
+		found = true
+		status.addSource(MetricDepreciationAndAmortization, source)
+		if source.Derivation != "" {
+			derivations = append(derivations, part+" "+source.Derivation)
 		}
 	}
 
-	return nil
+	if !found {
+		status.addMissing(MetricDepreciationAndAmortization)
+		return
+	}
+	*value = Float(total)
+	if len(derivations) > 0 {
+		status.addDerivation(MetricDepreciationAndAmortization, strings.Join(derivations, "; "))
+	}
 }
 
-// GetQuarterlyEBITDAAnalysis retrieves EBITDA metrics for the 4 most recent 10-Q filings
-func (c *Client) GetQuarterlyEBITDAAnalysis(cik string) (*QuarterlyEBITDAAnalysis, error) {
-	// Get the 4 most recent 10-Q filings
-	filings, err := c.GetMostRecent4TenQs(cik)
+// GetQuarterlyEBITDAAnalysis retrieves EBITDA metrics for the 4 most recent fiscal quarters, one per 10-Q
+// or 10-K filing, deriving the fourth quarter of a 10-K as for GetQuarterlyCashFlowAnalysis
+func (c *Client) GetQuarterlyEBITDAAnalysis(ctx context.Context, cik string) (*QuarterlyEBITDAAnalysis, error) {
+	// Get the filings of the 4 most recent quarters
+	filings, err := c.GetMostRecentQuarterlyFilings(ctx, cik, 4)
 	if err != nil {
-		return nil, fmt.Errorf("error getting recent 10-Q filings: %w", err)
+		return nil, fmt.Errorf("error getting recent quarterly filings: %w", err)
 	}
 
 	// Get company facts once (we'll reuse this for all quarters)
-	facts, err := c.GetCompanyFacts(cik)
+	facts, err := c.GetCompanyFacts(ctx, cik)
 	if err != nil {
 		return nil, fmt.Errorf("error getting company facts: %w", err)
 	}
@@ -739,10 +918,14 @@ func (c *Client) GetQuarterlyEBITDAAnalysis(cik string) (*QuarterlyEBITDAAnalysi
 		CIK:         facts.GetCIKString(),
 		Quarters:    make([]EBITDAMetrics, 0, len(filings)),
 	}
+	concepts := c.companyConcepts(ctx, cik)
 
 	// Parse EBITDA metrics for each filing
 	for _, filing := range filings {
-		metrics, err := c.ParseEBITDAMetricsFromFacts(facts, &filing)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		metrics, err := c.parseEBITDAMetrics(facts, &filing, DurationQuarter, concepts)
 		if err != nil {
 			log.Printf("Warning: Could not parse EBITDA metrics for filing %s: %v", filing.AccessionNumber, err)
 			continue
@@ -751,7 +934,7 @@ func (c *Client) GetQuarterlyEBITDAAnalysis(cik string) (*QuarterlyEBITDAAnalysi
 	}
 
 	if len(analysis.Quarters) == 0 {
-		return nil, fmt.Errorf("no EBITDA metrics could be extracted from any 10-Q filings")
+		return nil, fmt.Errorf("no EBITDA metrics could be extracted from any quarterly filings")
 	}
 
 	return analysis, nil
@@ -1,6 +1,7 @@
 package edgar
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -64,7 +65,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 50000000000,
 								"end": "2023-12-30"
 							}
@@ -75,7 +81,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 5000000000,
 								"end": "2023-12-30"
 							}
@@ -86,7 +97,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 100000000000,
 								"end": "2023-12-30"
 							}
@@ -97,7 +113,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 25000000000,
 								"end": "2023-12-30"
 							}
@@ -108,7 +129,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 1000000000,
 								"end": "2023-12-30"
 							}
@@ -119,7 +145,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 3000000000,
 								"end": "2023-12-30"
 							}
@@ -130,7 +161,12 @@ func getMockCompanyFacts() string {
 					"units": {
 						"USD": [
 							{
+								"start": "2023-10-01",
+								"accn": "0000320193-24-000007",
+								"fy": 2024,
+								"fp": "Q1",
 								"form": "10-Q",
+								"filed": "2024-02-01",
 								"val": 2000000000,
 								"end": "2023-12-30"
 							}
@@ -211,7 +247,7 @@ func TestClient_makeRequest(t *testing.T) {
 			defer server.Close()
 
 			client := NewClient()
-			body, err := client.makeRequest(server.URL)
+			body, err := client.makeRequest(context.Background(), server.URL)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -224,6 +260,36 @@ func TestClient_makeRequest(t *testing.T) {
 	}
 }
 
+func TestClient_makeRequest_ContextCanceled(t *testing.T) {
+	server := createMockServer(`{"test": "data"}`, http.StatusOK)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient()
+	_, err := client.makeRequest(ctx, server.URL)
+
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_GetQuarterlyEBITDAAnalysis_ContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClient(WithBaseURL(server.URL))
+	analysis, err := client.GetQuarterlyEBITDAAnalysis(ctx, mockCIK)
+
+	assert.Nil(t, analysis)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
 func TestCompanyFacts_GetCIKString(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -267,10 +333,7 @@ func TestClient_GetCompanyFacts(t *testing.T) {
 		defer server.Close()
 
 		// Create a client with custom base URL for testing
-		client := &Client{
-			httpClient: &http.Client{Timeout: time.Second * 30},
-			userAgent:  userAgent,
-		}
+		client := NewClient()
 
 		// We'll test with the real API structure but mock the HTTP response
 		// by temporarily replacing the base URL in the actual request
@@ -287,7 +350,7 @@ func TestClient_GetCompanyFacts(t *testing.T) {
 			},
 		}
 
-		facts, err := client.GetCompanyFacts(mockCIK)
+		facts, err := client.GetCompanyFacts(context.Background(), mockCIK)
 
 		require.NoError(t, err)
 		assert.NotNil(t, facts)
@@ -299,10 +362,7 @@ func TestClient_GetCompanyFacts(t *testing.T) {
 		server := createMockServer(`{"error": "internal error"}`, http.StatusInternalServerError)
 		defer server.Close()
 
-		client := &Client{
-			httpClient: &http.Client{Timeout: time.Second * 30},
-			userAgent:  userAgent,
-		}
+		client := NewClient()
 
 		originalURL := fmt.Sprintf("%s/api/xbrl/companyfacts/CIK%s.json", "https://data.sec.gov", mockCIK)
 		testURL := server.URL
@@ -316,7 +376,7 @@ func TestClient_GetCompanyFacts(t *testing.T) {
 			},
 		}
 
-		facts, err := client.GetCompanyFacts(mockCIK)
+		facts, err := client.GetCompanyFacts(context.Background(), mockCIK)
 
 		assert.Error(t, err)
 		assert.Nil(t, facts)
@@ -328,10 +388,7 @@ func TestClient_GetCompanySubmissions(t *testing.T) {
 		server := createMockServer(getMockCompanySubmissions(), http.StatusOK)
 		defer server.Close()
 
-		client := &Client{
-			httpClient: &http.Client{Timeout: time.Second * 30},
-			userAgent:  userAgent,
-		}
+		client := NewClient()
 
 		originalURL := fmt.Sprintf("%s/submissions/CIK%s.json", "https://data.sec.gov", mockCIK)
 		testURL := server.URL
@@ -345,7 +402,7 @@ func TestClient_GetCompanySubmissions(t *testing.T) {
 			},
 		}
 
-		submissions, err := client.GetCompanySubmissions(mockCIK)
+		submissions, err := client.GetCompanySubmissions(context.Background(), mockCIK)
 
 		require.NoError(t, err)
 		assert.NotNil(t, submissions)
@@ -376,11 +433,57 @@ func TestClient_parseFilings(t *testing.T) {
 
 	assert.Len(t, filings, 2)
 	assert.Equal(t, "0000320193-24-000007", filings[0].AccessionNumber)
-	assert.Equal(t, "2024-02-01", filings[0].FilingDate)
-	assert.Equal(t, "2023-12-30", filings[0].ReportDate)
+	assert.Equal(t, "2024-02-01", filings[0].FilingDate.String())
+	assert.Equal(t, "2023-12-30", filings[0].ReportDate.String())
 	assert.Equal(t, "10-Q", filings[0].Form)
 }
 
+func TestClient_parseFilings_TypedFields(t *testing.T) {
+	client := NewClient()
+
+	recentData := map[string][]interface{}{
+		"accessionNumber":       {"0000320193-24-000010", "0000320193-24-000009"},
+		"filingDate":            {"2024-05-02", "2024-03-15"},
+		"reportDate":            {"2024-05-02", ""},
+		"acceptanceDateTime":    {"2024-05-02T16:30:28.000Z", "2024-03-15T09:00:00.000Z"},
+		"act":                   {"34", ""},
+		"form":                  {"8-K", "3"},
+		"items":                 {"2.02,9.01", ""},
+		"core_type":             {"8-K", "3"},
+		"size":                  {123456, 4321},
+		"isXBRL":                {0, 1},
+		"isInlineXBRL":          {0, 1},
+		"primaryDocument":       {"aapl-20240502.htm", "xslF345X02/wf-form3.xml"},
+		"primaryDocDescription": {"8-K", "FORM 3"},
+	}
+
+	filings := client.parseFilings(recentData)
+	require.Len(t, filings, 2)
+
+	assert.Equal(t, NewDate(2024, time.May, 2), filings[0].FilingDate)
+	assert.Equal(t, time.Date(2024, time.May, 2, 16, 30, 28, 0, time.UTC), filings[0].AcceptanceDateTime)
+	assert.Equal(t, "34", filings[0].Act)
+	assert.Equal(t, []string{"2.02", "9.01"}, filings[0].Items)
+	assert.Equal(t, "8-K", filings[0].CoreType)
+	assert.Equal(t, int64(123456), filings[0].Size)
+	assert.False(t, filings[0].IsXBRL)
+
+	assert.True(t, filings[1].ReportDate.IsZero())
+	assert.Nil(t, filings[1].Items)
+	assert.True(t, filings[1].IsXBRL)
+	assert.True(t, filings[1].IsInlineXBRL)
+	assert.Empty(t, filings[1].FileNumber, "missing columns yield zero values")
+
+	// Filings survive a JSON round trip unchanged
+	data, err := json.Marshal(filings)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"filingDate":"2024-05-02"`)
+
+	var decoded []Filing
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, filings, decoded)
+}
+
 func TestClient_toString(t *testing.T) {
 	client := NewClient()
 
@@ -410,10 +513,7 @@ func TestClient_GetMostRecent10Q(t *testing.T) {
 		server := createMockServer(getMockCompanySubmissions(), http.StatusOK)
 		defer server.Close()
 
-		client := &Client{
-			httpClient: &http.Client{Timeout: time.Second * 30},
-			userAgent:  userAgent,
-		}
+		client := NewClient()
 
 		originalURL := fmt.Sprintf("%s/submissions/CIK%s.json", "https://data.sec.gov", mockCIK)
 		testURL := server.URL
@@ -427,12 +527,12 @@ func TestClient_GetMostRecent10Q(t *testing.T) {
 			},
 		}
 
-		filing, err := client.GetMostRecent10Q(mockCIK)
+		filing, err := client.GetMostRecent10Q(context.Background(), mockCIK)
 
 		require.NoError(t, err)
 		assert.NotNil(t, filing)
 		assert.Equal(t, "0000320193-24-000007", filing.AccessionNumber)
-		assert.Equal(t, "2024-02-01", filing.FilingDate)
+		assert.Equal(t, "2024-02-01", filing.FilingDate.String())
 		assert.Equal(t, "10-Q", filing.Form)
 	})
 
@@ -461,10 +561,7 @@ func TestClient_GetMostRecent10Q(t *testing.T) {
 		server := createMockServer(noTenQResponse, http.StatusOK)
 		defer server.Close()
 
-		client := &Client{
-			httpClient: &http.Client{Timeout: time.Second * 30},
-			userAgent:  userAgent,
-		}
+		client := NewClient()
 
 		originalURL := fmt.Sprintf("%s/submissions/CIK%s.json", "https://data.sec.gov", mockCIK)
 		testURL := server.URL
@@ -478,7 +575,7 @@ func TestClient_GetMostRecent10Q(t *testing.T) {
 			},
 		}
 
-		filing, err := client.GetMostRecent10Q(mockCIK)
+		filing, err := client.GetMostRecent10Q(context.Background(), mockCIK)
 
 		assert.Error(t, err)
 		assert.Nil(t, filing)
@@ -490,10 +587,7 @@ func TestClient_GetMostRecent4TenQs(t *testing.T) {
 	server := createMockServer(getMockCompanySubmissions(), http.StatusOK)
 	defer server.Close()
 
-	client := &Client{
-		httpClient: &http.Client{Timeout: time.Second * 30},
-		userAgent:  userAgent,
-	}
+	client := NewClient()
 
 	originalURL := fmt.Sprintf("%s/submissions/CIK%s.json", "https://data.sec.gov", mockCIK)
 	testURL := server.URL
@@ -507,78 +601,74 @@ func TestClient_GetMostRecent4TenQs(t *testing.T) {
 		},
 	}
 
-	filings, err := client.GetMostRecent4TenQs(mockCIK)
+	filings, err := client.GetMostRecent4TenQs(context.Background(), mockCIK)
 
 	require.NoError(t, err)
 	assert.Len(t, filings, 4) // Should return 4 most recent 10-Q filings
 
 	// Verify they are sorted by filing date (most recent first)
 	for i := 0; i < len(filings)-1; i++ {
-		assert.True(t, filings[i].FilingDate >= filings[i+1].FilingDate)
+		assert.False(t, filings[i].FilingDate.Before(filings[i+1].FilingDate.Time))
 	}
 }
 
-func TestClient_findValueForDate(t *testing.T) {
-	client := NewClient()
+func TestClient_GetQuarterlyCashFlowAnalysis_FourthQuarter(t *testing.T) {
+	client := NewClient(WithBaseURL(annualServer(t).URL), WithRateLimiter(nil))
 
-	dataArray := []interface{}{
-		map[string]interface{}{
-			"end":  "2023-12-30",
-			"form": "10-Q",
-			"val":  100.0,
-		},
-		map[string]interface{}{
-			"end":  "2023-09-30",
-			"form": "10-Q",
-			"val":  90.0,
-		},
-		map[string]interface{}{
-			"end":  "2023-12-30",
-			"form": "10-K",
-			"val":  110.0,
-		},
-	}
+	filings, err := client.GetMostRecentQuarterlyFilings(context.Background(), "0000320193", 2)
+	require.NoError(t, err)
+	require.Len(t, filings, 2)
+	assert.Equal(t, "10-Q", filings[0].Form)
+	assert.Equal(t, "10-K", filings[1].Form)
 
-	// Test exact date match with 10-Q form (should prefer this)
-	value := client.findValueForDate(dataArray, "2023-12-30")
-	assert.Equal(t, 100.0, value)
+	_, err = client.GetMostRecentQuarterlyFilings(context.Background(), "0000320193", 0)
+	assert.ErrorContains(t, err, "quarters must be at least 1")
 
-	// Test with no exact date match - both 10-Q forms have same score, ties broken by date
-	value = client.findValueForDate(dataArray, "2023-06-30")
-	assert.Equal(t, 100.0, value) // Should get 2023-12-30 10-Q (tie-breaker by more recent date)
+	analysis, err := client.GetQuarterlyCashFlowAnalysis(context.Background(), "0000320193")
+	require.NoError(t, err)
+	require.Len(t, analysis.Quarters, 3) // q1 and both 10-Ks
+
+	// The fourth quarter of the 10-K is its 12-month value minus the 9-month value
+	q4 := analysis.Quarters[1]
+	assert.Equal(t, "fy24", q4.AccessionNumber)
+	assert.Equal(t, Float(27), q4.NetCashFromOperatingActivities)
+	assert.Equal(t, "12M to 2024-09-28 minus 9M to 2024-06-29", q4.Derivations[MetricNetCashFromOperatingActivities])
 }
 
 func TestClient_extractMetric(t *testing.T) {
 	client := NewClient()
 
-	usGaap := map[string]interface{}{
-		"TestMetric": map[string]interface{}{
-			"units": map[string]interface{}{
-				"USD": []interface{}{
-					map[string]interface{}{
-						"end":  "2023-12-30",
-						"form": "10-Q",
-						"val":  1000000.0,
-					},
+	facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {
+		"TestMetric": {
+			Units: map[string][]Fact{
+				"USD": {
+					{Start: NewDate(2023, time.October, 1), End: NewDate(2023, time.December, 30), Accn: "a", Form: "10-Q", Val: 1000000.0},
+					{Start: NewDate(2023, time.July, 2), End: NewDate(2023, time.September, 30), Accn: "b", Form: "10-Q", Val: 900000.0},
 				},
 			},
 		},
-	}
+	}}}
 
-	var result float64
-	err := client.extractMetric(usGaap, []string{"TestMetric"}, &result, "2023-12-30")
+	source, err := client.extractMetric(facts, []ConceptCandidate{{Taxonomy: "us-gaap", Tag: "TestMetric"}}, FactSelector{
+		AccessionNumber: "a",
+		End:             NewDate(2023, time.December, 30),
+		Duration:        DurationQuarter,
+	})
 
 	assert.NoError(t, err)
-	assert.Equal(t, 1000000.0, result)
+	assert.Equal(t, 1000000.0, source.Value)
+	assert.Equal(t, "a", source.AccessionNumber)
+	assert.Equal(t, "TestMetric", source.Tag)
+	assert.Equal(t, "USD", source.Unit)
+	assert.Empty(t, source.Derivation)
 }
 
 func TestClient_extractMetric_NotFound(t *testing.T) {
 	client := NewClient()
 
-	usGaap := map[string]interface{}{}
+	facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {}}}
 
-	var result float64
-	err := client.extractMetric(usGaap, []string{"NonExistentMetric"}, &result, "2023-12-30")
+	_, err := client.extractMetric(facts, []ConceptCandidate{{Taxonomy: "us-gaap", Tag: "NonExistentMetric"}}, FactSelector{End: NewDate(2023, time.December, 30)})
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "metric not found")
@@ -587,41 +677,68 @@ func TestClient_extractMetric_NotFound(t *testing.T) {
 func TestEBITDACalculation(t *testing.T) {
 	// Test EBITDA calculation with sample data
 	metrics := &EBITDAMetrics{
-		NetIncome:                   25000000000,
-		InterestExpense:             1000000000,
-		IncomeTaxExpense:            3000000000,
-		DepreciationAndAmortization: 2000000000,
-		Revenue:                     100000000000,
+		NetIncome:                   Float(25000000000),
+		InterestExpense:             Float(1000000000),
+		IncomeTaxExpense:            Float(3000000000),
+		DepreciationAndAmortization: Float(2000000000),
+		Revenue:                     Float(100000000000),
 	}
 
-	// Calculate EBITDA
-	metrics.EBITDA = metrics.NetIncome + metrics.InterestExpense + metrics.IncomeTaxExpense + metrics.DepreciationAndAmortization
-
-	// Calculate EBITDA Margin
-	if metrics.Revenue != 0 {
-		metrics.EBITDAMargin = (metrics.EBITDA / metrics.Revenue) * 100
-	}
+	// Calculate EBITDA and EBITDA Margin
+	metrics.calculate()
 
 	expectedEBITDA := 31000000000.0 // 25B + 1B + 3B + 2B
 	expectedMargin := 31.0          // (31B / 100B) * 100
 
-	assert.Equal(t, expectedEBITDA, metrics.EBITDA)
-	assert.Equal(t, expectedMargin, metrics.EBITDAMargin)
+	assert.Equal(t, Float(expectedEBITDA), metrics.EBITDA)
+	assert.Equal(t, Float(expectedMargin), metrics.EBITDAMargin)
 }
 
 func TestFreeCashFlowCalculation(t *testing.T) {
 	// Test Free Cash Flow calculation
 	metrics := &CashFlowMetrics{
-		NetCashFromOperatingActivities: 50000000000,
-		CapitalExpenditures:            5000000000,
+		NetCashFromOperatingActivities: Float(50000000000),
+		CapitalExpenditures:            Float(5000000000),
 	}
 
 	// Calculate Free Cash Flow
-	metrics.FreeCashFlow = metrics.NetCashFromOperatingActivities - metrics.CapitalExpenditures
+	metrics.calculate()
 
 	expectedFCF := 45000000000.0 // 50B - 5B
 
-	assert.Equal(t, expectedFCF, metrics.FreeCashFlow)
+	assert.Equal(t, Float(expectedFCF), metrics.FreeCashFlow)
+}
+
+func TestClient_ParseCashFlowMetricsFromFacts_YearToDate(t *testing.T) {
+	client := NewClient()
+
+	q2 := NewDate(2024, time.March, 30)
+	facts := &CompanyFacts{
+		Entity: "Apple Inc.",
+		Facts: map[string]Taxonomy{
+			"us-gaap": {
+				"NetCashProvidedByUsedInOperatingActivities": {Units: map[string][]Fact{"USD": {
+					ytdFact(NewDate(2023, time.December, 30), 40, "10-Q", "2024-02-02"),
+					ytdFact(q2, 62, "10-Q", "2024-05-03"),
+				}}},
+				"PaymentsToAcquirePropertyPlantAndEquipment": {Units: map[string][]Fact{"USD": {
+					{Start: NewDate(2023, time.December, 31), End: q2, Val: 2, Form: "10-Q"},
+					ytdFact(q2, 5, "10-Q", "2024-05-03"),
+				}}},
+			},
+		},
+	}
+
+	metrics, err := client.ParseCashFlowMetricsFromFacts(facts, &Filing{Form: "10-Q", ReportDate: q2})
+	require.NoError(t, err)
+
+	assert.Equal(t, Float(22), metrics.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(2), metrics.CapitalExpenditures)
+	assert.Equal(t, Float(20), metrics.FreeCashFlow)
+	assert.False(t, metrics.Incomplete)
+	assert.Equal(t, map[string]string{
+		"netCashFromOperatingActivities": "6M to 2024-03-30 minus 3M to 2023-12-30",
+	}, metrics.Derivations)
 }
 
 // Benchmark tests
@@ -0,0 +1,134 @@
+package edgar
+
+import (
+	"slices"
+	"sort"
+)
+
+// CompanyConcept represents the companyconcept API response: every fact a company reported for a single tag
+type CompanyConcept struct {
+	CIK        interface{} `json:"cik"` // Can be string or number
+	Taxonomy   string      `json:"taxonomy"`
+	Tag        string      `json:"tag"`
+	EntityName string      `json:"entityName"`
+	Concept                // Label, description and facts grouped by unit
+}
+
+// GetCIKString returns the CIK as a string
+func (cc *CompanyConcept) GetCIKString() string {
+	return cikString(cc.CIK)
+}
+
+// PeriodDuration classifies the length of a fact's reporting period
+type PeriodDuration int
+
+const (
+	DurationAny        PeriodDuration = iota // Matches every fact when used in a SeriesFilter
+	DurationInstant                          // Point-in-time value, e.g. a balance sheet item
+	DurationQuarter                          // 80-100 days
+	DurationHalfYear                         // 170-200 days
+	DurationNineMonths                       // 260-290 days
+	DurationYear                             // 350-380 days
+	DurationOther                            // Any other period length
+)
+
+// String returns a short name for the duration, e.g. "3M"
+func (d PeriodDuration) String() string {
+	switch d {
+	case DurationAny:
+		return "any"
+	case DurationInstant:
+		return "instant"
+	case DurationQuarter:
+		return "3M"
+	case DurationHalfYear:
+		return "6M"
+	case DurationNineMonths:
+		return "9M"
+	case DurationYear:
+		return "12M"
+	default:
+		return "other"
+	}
+}
+
+// Duration classifies the length of the fact's period
+func (f Fact) Duration() PeriodDuration {
+	if f.IsInstant() {
+		return DurationInstant
+	}
+
+	days := f.Days()
+	switch {
+	case days >= 80 && days <= 100:
+		return DurationQuarter
+	case days >= 170 && days <= 200:
+		return DurationHalfYear
+	case days >= 260 && days <= 290:
+		return DurationNineMonths
+	case days >= 350 && days <= 380:
+		return DurationYear
+	default:
+		return DurationOther
+	}
+}
+
+// SeriesFilter selects the facts returned by Concept.Series. Empty fields match everything.
+type SeriesFilter struct {
+	Unit          string         // Unit of measure, defaults to "USD"
+	Forms         []string       // e.g. "10-Q", "10-K"
+	FiscalPeriods []string       // e.g. "Q1", "FY"
+	Duration      PeriodDuration // e.g. DurationQuarter
+	Dedupe        bool           // Keep only the most recently filed fact for each period
+}
+
+// Series returns the concept's facts matching filter, ordered by period end date.
+// Facts for the same period are ordered by filing date.
+func (c *Concept) Series(filter SeriesFilter) []Fact {
+	unit := filter.Unit
+	if unit == "" {
+		unit = "USD"
+	}
+
+	var series []Fact
+	for _, fact := range c.Facts(unit) {
+		if len(filter.Forms) > 0 && !slices.Contains(filter.Forms, fact.Form) {
+			continue
+		}
+		if len(filter.FiscalPeriods) > 0 && !slices.Contains(filter.FiscalPeriods, fact.FP) {
+			continue
+		}
+		if filter.Duration != DurationAny && fact.Duration() != filter.Duration {
+			continue
+		}
+		series = append(series, fact)
+	}
+
+	sort.SliceStable(series, func(i, j int) bool {
+		if !series[i].End.Equal(series[j].End.Time) {
+			return series[i].End.Before(series[j].End.Time)
+		}
+		if !series[i].Start.Equal(series[j].Start.Time) {
+			return series[i].Start.Before(series[j].Start.Time)
+		}
+		return series[i].Filed.Before(series[j].Filed.Time)
+	})
+
+	if filter.Dedupe {
+		series = dedupePeriods(series)
+	}
+
+	return series
+}
+
+// dedupePeriods keeps the last fact of each run of facts covering the same period
+func dedupePeriods(sorted []Fact) []Fact {
+	var deduped []Fact
+	for i, fact := range sorted {
+		if i+1 < len(sorted) && sorted[i+1].Start.Equal(fact.Start.Time) && sorted[i+1].End.Equal(fact.End.Time) {
+			continue
+		}
+		deduped = append(deduped, fact)
+	}
+	return deduped
+}
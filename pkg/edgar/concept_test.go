@@ -0,0 +1,116 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock companyconcept response with quarterly, year-to-date and annual facts
+func getMockCompanyConcept() string {
+	return `{
+		"cik": 320193,
+		"taxonomy": "us-gaap",
+		"tag": "Revenues",
+		"label": "Revenues",
+		"description": "Amount of revenue recognized.",
+		"entityName": "Apple Inc.",
+		"units": {
+			"USD": [
+				{"start": "2022-09-25", "end": "2023-09-30", "val": 383285000000, "accn": "0000320193-23-000106", "fy": 2023, "fp": "FY", "form": "10-K", "filed": "2023-11-03", "frame": "CY2023"},
+				{"start": "2023-04-02", "end": "2023-07-01", "val": 81797000000, "accn": "0000320193-23-000077", "fy": 2023, "fp": "Q3", "form": "10-Q", "filed": "2023-08-04", "frame": "CY2023Q2"},
+				{"start": "2022-09-25", "end": "2023-07-01", "val": 285735000000, "accn": "0000320193-23-000077", "fy": 2023, "fp": "Q3", "form": "10-Q", "filed": "2023-08-04"},
+				{"start": "2023-10-01", "end": "2023-12-30", "val": 119575000000, "accn": "0000320193-24-000006", "fy": 2024, "fp": "Q1", "form": "10-Q", "filed": "2024-02-02", "frame": "CY2023Q4"},
+				{"start": "2023-04-02", "end": "2023-07-01", "val": 81797000000, "accn": "0000320193-24-000069", "fy": 2024, "fp": "Q3", "form": "10-Q", "filed": "2024-08-02"}
+			]
+		}
+	}`
+}
+
+func TestClient_GetCompanyConcept(t *testing.T) {
+	server := createMockServer(getMockCompanyConcept(), http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	concept, err := client.GetCompanyConcept(context.Background(), mockCIK, "us-gaap", "Revenues")
+
+	require.NoError(t, err)
+	assert.Equal(t, "320193", concept.GetCIKString())
+	assert.Equal(t, "Apple Inc.", concept.EntityName)
+	assert.Equal(t, "Revenues", concept.Label)
+	assert.Equal(t, "Amount of revenue recognized.", concept.Description)
+
+	facts := concept.Facts("USD")
+	require.Len(t, facts, 5)
+	assert.Equal(t, "2022-09-25", facts[0].Start.String())
+	assert.Equal(t, "2023-09-30", facts[0].End.String())
+	assert.Equal(t, "0000320193-23-000106", facts[0].Accn)
+	assert.Equal(t, 2023, facts[0].FY)
+	assert.Equal(t, "FY", facts[0].FP)
+	assert.Equal(t, "2023-11-03", facts[0].Filed.String())
+	assert.Equal(t, "CY2023", facts[0].Frame)
+}
+
+func TestFact_Duration(t *testing.T) {
+	tests := []struct {
+		name     string
+		start    Date
+		expected PeriodDuration
+	}{
+		{"instant", Date{}, DurationInstant},
+		{"quarter", NewDate(2023, time.October, 1), DurationQuarter},
+		{"half year", NewDate(2023, time.July, 2), DurationHalfYear},
+		{"nine months", NewDate(2023, time.April, 2), DurationNineMonths},
+		{"year", NewDate(2023, time.January, 1), DurationYear},
+		{"other", NewDate(2023, time.November, 30), DurationOther},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact := Fact{Start: tt.start, End: NewDate(2023, time.December, 30)}
+			assert.Equal(t, tt.expected, fact.Duration())
+		})
+	}
+}
+
+func TestConcept_Series(t *testing.T) {
+	server := createMockServer(getMockCompanyConcept(), http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	concept, err := client.GetCompanyConcept(context.Background(), mockCIK, "us-gaap", "Revenues")
+	require.NoError(t, err)
+
+	t.Run("quarterly 10-Q values", func(t *testing.T) {
+		series := concept.Series(SeriesFilter{Forms: []string{"10-Q"}, Duration: DurationQuarter})
+
+		require.Len(t, series, 3)
+		assert.Equal(t, "2023-07-01", series[0].End.String())
+		assert.Equal(t, "2023-08-04", series[0].Filed.String())
+		assert.Equal(t, "2024-08-02", series[1].Filed.String()) // Same period, later filing
+		assert.Equal(t, "2023-12-30", series[2].End.String())
+	})
+
+	t.Run("dedupe keeps latest filing", func(t *testing.T) {
+		series := concept.Series(SeriesFilter{Duration: DurationQuarter, Dedupe: true})
+
+		require.Len(t, series, 2)
+		assert.Equal(t, "0000320193-24-000069", series[0].Accn)
+		assert.Equal(t, "0000320193-24-000006", series[1].Accn)
+	})
+
+	t.Run("fiscal period", func(t *testing.T) {
+		series := concept.Series(SeriesFilter{FiscalPeriods: []string{"FY"}})
+
+		require.Len(t, series, 1)
+		assert.Equal(t, DurationYear, series[0].Duration())
+	})
+
+	t.Run("unknown unit", func(t *testing.T) {
+		assert.Empty(t, concept.Series(SeriesFilter{Unit: "shares"}))
+	})
+}
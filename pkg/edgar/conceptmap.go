@@ -0,0 +1,377 @@
+package edgar
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Metric names used as keys of a ConceptMap. They match the JSON fields of the metrics.
+const (
+	MetricNetCashFromOperatingActivities = "netCashFromOperatingActivities"
+	MetricCapitalExpenditures            = "capitalExpenditures"
+	MetricRevenue                        = "revenue"
+	MetricNetIncome                      = "netIncome"
+	MetricInterestExpense                = "interestExpense"
+	MetricIncomeTaxExpense               = "incomeTaxExpense"
+	MetricDepreciationAndAmortization    = "depreciationAndAmortization"
+	MetricDepreciation                   = "depreciation" // Summed with amortization when no combined concept is found
+	MetricAmortization                   = "amortization"
+
+	MetricCash                      = "cash"
+	MetricShortTermInvestments      = "shortTermInvestments"
+	MetricReceivables               = "receivables"
+	MetricInventory                 = "inventory"
+	MetricTotalCurrentAssets        = "totalCurrentAssets"
+	MetricPropertyPlantAndEquipment = "propertyPlantAndEquipment"
+	MetricGoodwill                  = "goodwill"
+	MetricTotalAssets               = "totalAssets"
+	MetricTotalCurrentLiabilities   = "totalCurrentLiabilities"
+	MetricShortTermDebt             = "shortTermDebt"
+	MetricLongTermDebt              = "longTermDebt"
+	MetricTotalLiabilities          = "totalLiabilities"
+	MetricStockholdersEquity        = "stockholdersEquity"
+
+	MetricCostOfRevenue                   = "costOfRevenue"
+	MetricGrossProfit                     = "grossProfit"
+	MetricSellingGeneralAndAdministrative = "sellingGeneralAndAdministrative"
+	MetricResearchAndDevelopment          = "researchAndDevelopment"
+	MetricOperatingIncome                 = "operatingIncome"
+	MetricEPSBasic                        = "epsBasic"
+	MetricEPSDiluted                      = "epsDiluted"
+	MetricSharesBasic                     = "sharesBasic" // Weighted average shares outstanding
+	MetricSharesDiluted                   = "sharesDiluted"
+
+	MetricStockBasedCompensation         = "stockBasedCompensation"
+	MetricChangeInReceivables            = "changeInReceivables" // Working capital changes are their effect on cash
+	MetricChangeInInventories            = "changeInInventories"
+	MetricChangeInPayables               = "changeInPayables"
+	MetricChangeInWorkingCapital         = "changeInWorkingCapital"
+	MetricNetCashFromInvestingActivities = "netCashFromInvestingActivities"
+	MetricDividendsPaid                  = "dividendsPaid"
+	MetricShareRepurchases               = "shareRepurchases"
+	MetricNetCashFromFinancingActivities = "netCashFromFinancingActivities"
+)
+
+// Sign rules applied to the value of a concept
+const (
+	SignAsReported = ""       // Use the value as reported
+	SignNegate     = "negate" // Flip the sign, e.g. for outflows reported as negative numbers
+	SignAbsolute   = "abs"    // Use the magnitude regardless of how the filer signed it
+)
+
+//go:embed conceptmap.yaml
+var defaultConceptMapYAML []byte
+
+// defaultConcepts is the map used by clients without WithConceptMap
+var defaultConcepts = sync.OnceValue(DefaultConceptMap)
+
+// ConceptCandidate is an XBRL concept a metric can be read from
+type ConceptCandidate struct {
+	Taxonomy string `yaml:"taxonomy,omitempty" json:"taxonomy,omitempty"` // Defaults to us-gaap
+	Tag      string `yaml:"tag" json:"tag"`
+	Unit     string `yaml:"unit,omitempty" json:"unit,omitempty"` // Defaults to the reporting currency, then other units in it
+	Sign     string `yaml:"sign,omitempty" json:"sign,omitempty"` // One of the Sign rules
+
+	override string // Concept map override the candidate came from, set by ForCompany
+}
+
+// String returns the candidate's concept, e.g. "us-gaap:Revenues"
+func (cc ConceptCandidate) String() string {
+	return cc.Taxonomy + ":" + cc.Tag
+}
+
+// units returns the units to read the candidate's concept in, in order of preference: its own unit, or
+// those denominated in the filer's reporting currency (USD if unknown)
+func (cc ConceptCandidate) units(concept *Concept, currency string) []string {
+	if cc.Unit != "" {
+		return []string{cc.Unit}
+	}
+	if currency == "" {
+		currency = "USD"
+	}
+	return concept.currencyUnits(currency)
+}
+
+// apply adjusts a reported value by the candidate's sign rule
+func (cc ConceptCandidate) apply(v float64) float64 {
+	switch cc.Sign {
+	case SignNegate:
+		return -v
+	case SignAbsolute:
+		return math.Abs(v)
+	default:
+		return v
+	}
+}
+
+// ConceptMap maps each metric to the concepts it is read from, tried in order until one has a fact
+// for the selected period. SIC and Companies replace the candidates of individual metrics for an
+// industry (keyed by SIC code) or a company (keyed by CIK); a company's override wins over its industry's.
+type ConceptMap struct {
+	Metrics   map[string][]ConceptCandidate            `yaml:"metrics,omitempty" json:"metrics,omitempty"`
+	SIC       map[string]map[string][]ConceptCandidate `yaml:"sic,omitempty" json:"sic,omitempty"`
+	Companies map[string]map[string][]ConceptCandidate `yaml:"companies,omitempty" json:"companies,omitempty"`
+}
+
+// DefaultConceptMap returns a copy of the embedded default map, which callers may modify
+func DefaultConceptMap() *ConceptMap {
+	m, err := parseConceptMap(defaultConceptMapYAML, false)
+	if err != nil {
+		panic(fmt.Sprintf("invalid default concept map: %v", err))
+	}
+	return m
+}
+
+// LoadConceptMap reads a concept map from a YAML file, or a JSON file if its extension is .json,
+// and merges it over the default map: each metric it lists replaces that metric's default candidates.
+func LoadConceptMap(path string) (*ConceptMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading concept map: %w", err)
+	}
+
+	m, err := parseConceptMap(data, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing concept map %s: %w", path, err)
+	}
+
+	return DefaultConceptMap().merge(m), nil
+}
+
+// parseConceptMap decodes and validates a concept map, rejecting unknown fields
+func parseConceptMap(data []byte, isJSON bool) (*ConceptMap, error) {
+	var m ConceptMap
+	var err error
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&m)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&m)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if err := m.normalize(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// normalize fills in default taxonomies, validates candidates and keys companies by CIK without leading zeros
+func (m *ConceptMap) normalize() error {
+	if err := normalizeCandidates("", m.Metrics); err != nil {
+		return err
+	}
+
+	for sic, metrics := range m.SIC {
+		if err := normalizeCandidates("sic "+sic+": ", metrics); err != nil {
+			return err
+		}
+	}
+
+	companies := make(map[string]map[string][]ConceptCandidate, len(m.Companies))
+	for cik, metrics := range m.Companies {
+		key, err := normalizeCIK(cik)
+		if err != nil {
+			return err
+		}
+		if err := normalizeCandidates("company "+cik+": ", metrics); err != nil {
+			return err
+		}
+		companies[key] = metrics
+	}
+	m.Companies = companies
+
+	return nil
+}
+
+// normalizeCandidates checks the candidates of each metric, prefixing errors with scope
+func normalizeCandidates(scope string, metrics map[string][]ConceptCandidate) error {
+	for metric, candidates := range metrics {
+		for i := range candidates {
+			cc := &candidates[i]
+			if cc.Tag == "" {
+				return fmt.Errorf("%s%s: candidate %d has no tag", scope, metric, i+1)
+			}
+			if cc.Taxonomy == "" {
+				cc.Taxonomy = "us-gaap"
+			}
+			switch cc.Sign {
+			case SignAsReported, SignNegate, SignAbsolute:
+			default:
+				return fmt.Errorf("%s%s: unknown sign rule %q for %s", scope, metric, cc.Sign, cc)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeCIK strips the leading zeros of a CIK
+func normalizeCIK(cik string) (string, error) {
+	n, err := strconv.ParseInt(cik, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIK %q: %w", cik, err)
+	}
+	return strconv.FormatInt(n, 10), nil
+}
+
+// merge replaces the candidates of every metric o lists, by scope, and returns m
+func (m *ConceptMap) merge(o *ConceptMap) *ConceptMap {
+	if m.Metrics == nil {
+		m.Metrics = make(map[string][]ConceptCandidate)
+	}
+	for metric, candidates := range o.Metrics {
+		m.Metrics[metric] = candidates
+	}
+
+	m.SIC = mergeOverrides(m.SIC, o.SIC)
+	m.Companies = mergeOverrides(m.Companies, o.Companies)
+	return m
+}
+
+// mergeOverrides merges the per-metric overrides of src into dst
+func mergeOverrides(dst, src map[string]map[string][]ConceptCandidate) map[string]map[string][]ConceptCandidate {
+	for key, metrics := range src {
+		if dst == nil {
+			dst = make(map[string]map[string][]ConceptCandidate)
+		}
+		if dst[key] == nil {
+			dst[key] = make(map[string][]ConceptCandidate)
+		}
+		for metric, candidates := range metrics {
+			dst[key][metric] = candidates
+		}
+	}
+	return dst
+}
+
+// ForCompany returns the candidates of every metric for a company, applying the overrides for its SIC code
+// and then those for its CIK. Either may be empty.
+func (m *ConceptMap) ForCompany(cik, sic string) map[string][]ConceptCandidate {
+	concepts := make(map[string][]ConceptCandidate, len(m.Metrics))
+	for metric, candidates := range m.Metrics {
+		concepts[metric] = candidates
+	}
+
+	for metric, candidates := range m.SIC[sic] {
+		concepts[metric] = overridden(candidates, "SIC "+sic)
+	}
+
+	if key, err := normalizeCIK(cik); err == nil {
+		for metric, candidates := range m.Companies[key] {
+			concepts[metric] = overridden(candidates, "CIK "+key)
+		}
+	}
+
+	return concepts
+}
+
+// overridden returns a copy of candidates marked as coming from the named override
+func overridden(candidates []ConceptCandidate, override string) []ConceptCandidate {
+	marked := slices.Clone(candidates)
+	for i := range marked {
+		marked[i].override = override
+	}
+	return marked
+}
+
+// conceptMap returns the client's concept map
+func (c *Client) conceptMap() *ConceptMap {
+	if c.concepts != nil {
+		return c.concepts
+	}
+	return defaultConcepts()
+}
+
+// rememberSIC records a company's SIC code, as reported in its submissions, for choosing concept overrides
+func (c *Client) rememberSIC(cik, sic string) {
+	if key, err := normalizeCIK(cik); err == nil && sic != "" {
+		c.sics.Store(key, sic)
+	}
+}
+
+// lookupSIC returns a company's SIC code, fetching its submissions unless the code is known already. It
+// returns "" without a request if the concept map has no SIC overrides, or if the lookup fails.
+func (c *Client) lookupSIC(ctx context.Context, cik string) string {
+	if len(c.conceptMap().SIC) == 0 {
+		return ""
+	}
+	if sic := c.cachedSIC(cik); sic != "" {
+		return sic
+	}
+
+	submissions, err := c.GetCompanySubmissions(ctx, cik)
+	if err != nil {
+		log.Printf("Warning: Could not look up the SIC code of CIK %s, using the default concepts: %v", cik, err)
+		return ""
+	}
+	return submissions.SIC
+}
+
+// cachedSIC returns the SIC code remembered from a company's submissions, or "" if none were fetched
+func (c *Client) cachedSIC(cik string) string {
+	key, err := normalizeCIK(cik)
+	if err != nil {
+		return ""
+	}
+	if v, ok := c.sics.Load(key); ok {
+		return v.(string)
+	}
+	return ""
+}
+
+// conceptsFor returns the candidates of every metric for a company with the given SIC code. An empty sic
+// falls back to the code remembered from the company's submissions; without one only the default and
+// company-level concepts apply
+func (c *Client) conceptsFor(cik, sic string) map[string][]ConceptCandidate {
+	if sic == "" {
+		sic = c.cachedSIC(cik)
+	}
+	return c.conceptMap().ForCompany(cik, sic)
+}
+
+// companyConcepts returns the candidates of every metric for a company, looking up its SIC code first
+// so that the concept map's SIC overrides apply
+func (c *Client) companyConcepts(ctx context.Context, cik string) map[string][]ConceptCandidate {
+	return c.conceptsFor(cik, c.lookupSIC(ctx, cik))
+}
+
+// checkTaxonomies returns an error if facts include none of the taxonomies the candidates refer to
+func checkTaxonomies(facts *CompanyFacts, concepts map[string][]ConceptCandidate) error {
+	seen := make(map[string]bool)
+	for _, candidates := range concepts {
+		for _, cc := range candidates {
+			if _, ok := facts.Taxonomy(cc.Taxonomy); ok {
+				return nil
+			}
+			seen[cc.Taxonomy] = true
+		}
+	}
+
+	taxonomies := make([]string, 0, len(seen))
+	for taxonomy := range seen {
+		taxonomies = append(taxonomies, taxonomy)
+	}
+	sort.Strings(taxonomies)
+	return fmt.Errorf("%s taxonomy not found", strings.Join(taxonomies, ", "))
+}
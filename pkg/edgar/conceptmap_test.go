@@ -0,0 +1,281 @@
+package edgar
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConceptMap(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestDefaultConceptMap(t *testing.T) {
+	m := DefaultConceptMap()
+
+	for _, metric := range []string{
+		MetricNetCashFromOperatingActivities, MetricCapitalExpenditures, MetricRevenue, MetricNetIncome,
+		MetricInterestExpense, MetricIncomeTaxExpense, MetricDepreciationAndAmortization,
+		MetricDepreciation, MetricAmortization,
+		MetricCash, MetricShortTermInvestments, MetricReceivables, MetricInventory, MetricTotalCurrentAssets,
+		MetricPropertyPlantAndEquipment, MetricGoodwill, MetricTotalAssets, MetricTotalCurrentLiabilities,
+		MetricShortTermDebt, MetricLongTermDebt, MetricTotalLiabilities, MetricStockholdersEquity,
+	} {
+		assert.NotEmpty(t, m.Metrics[metric], metric)
+		taxonomies := make(map[string]bool)
+		for _, cc := range m.Metrics[metric] {
+			taxonomies[cc.Taxonomy] = true
+			assert.Empty(t, cc.Unit)
+			assert.Equal(t, SignAsReported, cc.Sign)
+		}
+		assert.Equal(t, map[string]bool{"us-gaap": true, "ifrs-full": true}, taxonomies, metric)
+	}
+	assert.Equal(t, "us-gaap:Revenues", m.Metrics[MetricRevenue][0].String())
+	assert.Len(t, m.Metrics[MetricCapitalExpenditures], 6)
+	assert.Equal(t, "ifrs-full:PurchaseOfPropertyPlantAndEquipmentClassifiedAsInvestingActivities", m.Metrics[MetricCapitalExpenditures][4].String())
+	assert.Empty(t, m.Metrics[MetricEPSDiluted][0].Unit, "per-share units follow the reporting currency")
+	assert.Equal(t, "shares", m.Metrics[MetricSharesDiluted][0].Unit)
+	assert.Equal(t, SignNegate, m.Metrics[MetricChangeInReceivables][0].Sign)
+
+	// Each call returns a copy
+	m.Metrics[MetricRevenue] = nil
+	assert.NotEmpty(t, DefaultConceptMap().Metrics[MetricRevenue])
+}
+
+func TestLoadConceptMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "mapping.yaml",
+			content: `
+metrics:
+  revenue:
+    - tag: SalesRevenueNet
+sic:
+  "6021":
+    revenue:
+      - tag: RevenuesNetOfInterestExpense
+companies:
+  "0000320193":
+    capitalExpenditures:
+      - tag: PaymentsToAcquireProductiveAssets
+        sign: abs
+      - taxonomy: ifrs-full
+        tag: PurchaseOfPropertyPlantAndEquipment
+        unit: EUR
+`,
+		},
+		{
+			name: "json",
+			file: "mapping.json",
+			content: `{
+  "metrics": {"revenue": [{"tag": "SalesRevenueNet"}]},
+  "sic": {"6021": {"revenue": [{"tag": "RevenuesNetOfInterestExpense"}]}},
+  "companies": {"0000320193": {"capitalExpenditures": [
+    {"tag": "PaymentsToAcquireProductiveAssets", "sign": "abs"},
+    {"taxonomy": "ifrs-full", "tag": "PurchaseOfPropertyPlantAndEquipment", "unit": "EUR"}
+  ]}}
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := LoadConceptMap(writeConceptMap(t, tt.file, tt.content))
+			require.NoError(t, err)
+
+			// Listed metrics replace the defaults, others are kept
+			assert.Equal(t, []ConceptCandidate{{Taxonomy: "us-gaap", Tag: "SalesRevenueNet"}}, m.Metrics[MetricRevenue])
+			assert.Equal(t, DefaultConceptMap().Metrics[MetricNetIncome], m.Metrics[MetricNetIncome])
+
+			assert.Equal(t, "RevenuesNetOfInterestExpense", m.SIC["6021"][MetricRevenue][0].Tag)
+			assert.Equal(t, []ConceptCandidate{
+				{Taxonomy: "us-gaap", Tag: "PaymentsToAcquireProductiveAssets", Sign: SignAbsolute},
+				{Taxonomy: "ifrs-full", Tag: "PurchaseOfPropertyPlantAndEquipment", Unit: "EUR"},
+			}, m.Companies["320193"][MetricCapitalExpenditures])
+		})
+	}
+}
+
+func TestLoadConceptMap_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unknown sign rule",
+			content: "metrics:\n  revenue:\n    - tag: Revenues\n      sign: flip\n",
+			wantErr: `unknown sign rule "flip"`,
+		},
+		{
+			name:    "missing tag",
+			content: "sic:\n  \"6021\":\n    revenue:\n      - taxonomy: us-gaap\n",
+			wantErr: "sic 6021: revenue: candidate 1 has no tag",
+		},
+		{
+			name:    "unknown field",
+			content: "metrics:\n  revenue:\n    - concept: Revenues\n",
+			wantErr: "concept",
+		},
+		{
+			name:    "invalid CIK",
+			content: "companies:\n  AAPL:\n    revenue:\n      - tag: Revenues\n",
+			wantErr: `invalid CIK "AAPL"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConceptMap(writeConceptMap(t, "mapping.yaml", tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	_, err := LoadConceptMap(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "error reading concept map")
+}
+
+func TestConceptMap_ForCompany(t *testing.T) {
+	m := &ConceptMap{
+		Metrics: map[string][]ConceptCandidate{
+			MetricRevenue:   {{Taxonomy: "us-gaap", Tag: "Revenues"}},
+			MetricNetIncome: {{Taxonomy: "us-gaap", Tag: "NetIncomeLoss"}},
+		},
+		SIC: map[string]map[string][]ConceptCandidate{
+			"6021": {
+				MetricRevenue:   {{Taxonomy: "us-gaap", Tag: "RevenuesNetOfInterestExpense"}},
+				MetricNetIncome: {{Taxonomy: "us-gaap", Tag: "ProfitLoss"}},
+			},
+		},
+		Companies: map[string]map[string][]ConceptCandidate{
+			"19617": {MetricRevenue: {{Taxonomy: "us-gaap", Tag: "InterestAndDividendIncomeOperating"}}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		cik           string
+		sic           string
+		wantRevenue   string
+		wantNetIncome string
+	}{
+		{"default", "320193", "3571", "Revenues", "NetIncomeLoss"},
+		{"industry override", "72971", "6021", "RevenuesNetOfInterestExpense", "ProfitLoss"},
+		{"company override wins over industry", "0000019617", "6021", "InterestAndDividendIncomeOperating", "ProfitLoss"},
+		{"company override without SIC", "19617", "", "InterestAndDividendIncomeOperating", "NetIncomeLoss"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			concepts := m.ForCompany(tt.cik, tt.sic)
+			assert.Equal(t, tt.wantRevenue, concepts[MetricRevenue][0].Tag)
+			assert.Equal(t, tt.wantNetIncome, concepts[MetricNetIncome][0].Tag)
+		})
+	}
+}
+
+func TestClient_ConceptMapOverrides(t *testing.T) {
+	q1 := NewDate(2023, time.December, 30)
+	filing := &Filing{AccessionNumber: "a", Form: "10-Q", ReportDate: q1}
+
+	fact := func(val float64) []Fact {
+		return []Fact{filedBy("a", ytdFact(q1, val, "10-Q", "2024-02-02"))}
+	}
+
+	facts := &CompanyFacts{CIK: "320193", Facts: map[string]Taxonomy{"us-gaap": {
+		"NetCashProvidedByUsedInOperatingActivities": {Units: map[string][]Fact{"USD": fact(40)}},
+		"PaymentsToAcquirePropertyPlantAndEquipment": {Units: map[string][]Fact{"USD": fact(5)}},
+		// Reported as a negative cash flow
+		"PaymentsToAcquireProductiveAssets": {Units: map[string][]Fact{"USD": fact(-8)}},
+	}}}
+
+	m := DefaultConceptMap()
+	m.SIC = map[string]map[string][]ConceptCandidate{
+		"3571": {MetricCapitalExpenditures: {{Taxonomy: "us-gaap", Tag: "PaymentsToAcquireProductiveAssets", Sign: SignNegate}}},
+	}
+
+	t.Run("default map", func(t *testing.T) {
+		metrics, err := NewClient().ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+		assert.Equal(t, Float(5), metrics.CapitalExpenditures)
+	})
+
+	t.Run("SIC override", func(t *testing.T) {
+		body, err := json.Marshal(facts)
+		require.NoError(t, err)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasPrefix(r.URL.Path, PathSubmissions):
+				w.Write([]byte(`{"cik": "320193", "sic": "3571", "name": "Apple Inc."}`))
+			case strings.HasPrefix(r.URL.Path, PathCompanyFacts):
+				w.Write(body)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		client := NewClient(WithBaseURL(server.URL), WithRateLimiter(nil), WithConceptMap(m))
+
+		// Pre-fetched facts carry no SIC code, so only the default concepts apply until it is known
+		metrics, err := client.ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+		assert.Equal(t, Float(5), metrics.CapitalExpenditures)
+		assert.Empty(t, metrics.Provenance[MetricCapitalExpenditures][0].Override)
+
+		// The SIC code is looked up before parsing, whatever the client fetched before
+		metrics, err = client.ParseCashFlowMetrics(context.Background(), "0000320193", filing)
+		require.NoError(t, err)
+		assert.Equal(t, Float(8), metrics.CapitalExpenditures)
+		assert.Equal(t, Float(32), metrics.FreeCashFlow)
+
+		source := metrics.Provenance[MetricCapitalExpenditures][0]
+		assert.Equal(t, "PaymentsToAcquireProductiveAssets", source.Tag)
+		assert.Equal(t, SignNegate, source.Sign)
+		assert.Equal(t, "SIC 3571", source.Override)
+		assert.Equal(t, 8.0, source.Value)
+		assert.Contains(t, source.String(), ", SIC 3571 override")
+
+		// Once the client has seen the submissions, parsing pre-fetched facts applies the SIC override too
+		metrics, err = client.ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+		assert.Equal(t, Float(8), metrics.CapitalExpenditures)
+		assert.Equal(t, "SIC 3571", metrics.Provenance[MetricCapitalExpenditures][0].Override)
+	})
+
+	t.Run("company override from pre-fetched facts", func(t *testing.T) {
+		m := DefaultConceptMap()
+		m.Companies = map[string]map[string][]ConceptCandidate{
+			"320193": {MetricCapitalExpenditures: {{Taxonomy: "us-gaap", Tag: "PaymentsToAcquireProductiveAssets", Sign: SignAbsolute}}},
+		}
+
+		metrics, err := NewClient(WithConceptMap(m)).ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+		assert.Equal(t, Float(8), metrics.CapitalExpenditures)
+		assert.Equal(t, "CIK 320193", metrics.Provenance[MetricCapitalExpenditures][0].Override)
+	})
+
+	t.Run("missing taxonomy", func(t *testing.T) {
+		dei := &CompanyFacts{CIK: "320193", Facts: map[string]Taxonomy{"dei": {}}}
+		_, err := NewClient().ParseCashFlowMetricsFromFacts(dei, filing)
+		assert.ErrorContains(t, err, "ifrs-full, us-gaap taxonomy not found")
+	})
+}
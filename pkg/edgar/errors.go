@@ -0,0 +1,71 @@
+package edgar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors returned (wrapped) by Client methods. Test for them with errors.Is.
+var (
+	// ErrNotFound is returned when EDGAR responds 404, e.g. for an unknown CIK
+	ErrNotFound = errors.New("edgar: not found")
+
+	// ErrRateLimited is returned when EDGAR throttles the caller (403 or 429)
+	ErrRateLimited = errors.New("edgar: rate limited")
+
+	// ErrNoFilings is returned when a company has no filings matching the request
+	ErrNoFilings = errors.New("edgar: no matching filings")
+
+	// ErrDecode is returned when a response body cannot be decoded
+	ErrDecode = errors.New("edgar: error decoding response")
+
+	// ErrNoUserAgent is returned by CheckUserAgent when no User-Agent identifying the caller is configured
+	ErrNoUserAgent = errors.New("edgar: no User-Agent configured")
+)
+
+// HTTPError is returned for responses with a non-200 status code.
+// It matches ErrNotFound or ErrRateLimited with errors.Is where appropriate.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+	Body       string
+	RetryAfter time.Duration // Parsed Retry-After header, zero if absent
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Is reports whether the status code corresponds to target
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusForbidden
+	default:
+		return false
+	}
+}
+
+// DecodeError is returned when a response body cannot be decoded. It matches ErrDecode with errors.Is.
+type DecodeError struct {
+	URL string
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("error decoding response: %v", e.Err)
+}
+
+// Unwrap returns the underlying decoding error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrDecode
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
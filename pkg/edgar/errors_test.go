@@ -0,0 +1,89 @@
+package edgar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPError_Is(t *testing.T) {
+	tests := []struct {
+		statusCode  int
+		notFound    bool
+		rateLimited bool
+	}{
+		{http.StatusNotFound, true, false},
+		{http.StatusForbidden, false, true},
+		{http.StatusTooManyRequests, false, true},
+		{http.StatusInternalServerError, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			err := &HTTPError{StatusCode: tt.statusCode}
+
+			assert.Equal(t, tt.notFound, errors.Is(err, ErrNotFound))
+			assert.Equal(t, tt.rateLimited, errors.Is(err, ErrRateLimited))
+		})
+	}
+}
+
+func TestClient_GetCompanyFacts_NotFound(t *testing.T) {
+	server := createMockServer(`<html>Not Found</html>`, http.StatusNotFound)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, err := client.GetCompanyFacts(context.Background(), "9999999999")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NotErrorIs(t, err, ErrRateLimited)
+
+	var httpErr *HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	assert.Equal(t, server.URL+"/api/xbrl/companyfacts/CIK9999999999.json", httpErr.URL)
+	assert.Equal(t, `<html>Not Found</html>`, httpErr.Body)
+}
+
+func TestClient_GetQuarterlyEBITDAAnalysis_RateLimited(t *testing.T) {
+	server := createMockServer(`Request Rate Threshold Exceeded`, http.StatusForbidden)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, err := client.GetQuarterlyEBITDAAnalysis(context.Background(), mockCIK)
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestClient_GetCompanySubmissions_DecodeError(t *testing.T) {
+	server := createMockServer(`{not json`, http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, err := client.GetCompanySubmissions(context.Background(), mockCIK)
+
+	assert.ErrorIs(t, err, ErrDecode)
+	assert.Contains(t, err.Error(), "error decoding response")
+
+	var decodeErr *DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Equal(t, server.URL+"/submissions/CIK"+mockCIK+".json", decodeErr.URL)
+}
+
+func TestClient_GetMostRecent10Q_NoFilings(t *testing.T) {
+	server := createMockServer(`{"cik": "320193", "filings": {"recent": {}}}`, http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	_, err := client.GetMostRecent10Q(context.Background(), mockCIK)
+	assert.ErrorIs(t, err, ErrNoFilings)
+
+	_, err = client.GetQuarterlyCashFlowAnalysis(context.Background(), mockCIK)
+	assert.ErrorIs(t, err, ErrNoFilings)
+}
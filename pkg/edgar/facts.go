@@ -0,0 +1,218 @@
+package edgar
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout is the date format used throughout the EDGAR APIs
+const DateLayout = "2006-01-02"
+
+// Date is a calendar date encoded in JSON as "2006-01-02". The zero value represents an absent date.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the Date for the given year, month and day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a "2006-01-02" date. An empty string yields the zero Date.
+func ParseDate(s string) (Date, error) {
+	if s == "" {
+		return Date{}, nil
+	}
+
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+
+	return Date{t}, nil
+}
+
+// String formats the date as "2006-01-02", or "" for the zero Date
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON encodes the date as "2006-01-02", or null for the zero Date
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes a "2006-01-02" string; null and "" yield the zero Date
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
+
+// CompanyFacts represents the company facts response
+type CompanyFacts struct {
+	CIK    interface{}         `json:"cik"` // Can be string or number
+	Entity string              `json:"entityName"`
+	Facts  map[string]Taxonomy `json:"facts"` // Keyed by taxonomy, e.g. "us-gaap" or "dei"
+}
+
+// Taxonomy maps concept tags (e.g. "Revenues") to their reported facts
+type Taxonomy map[string]*Concept
+
+// Concept is a single XBRL concept with its facts grouped by unit of measure
+type Concept struct {
+	Label       string            `json:"label"`
+	Description string            `json:"description"`
+	Units       map[string][]Fact `json:"units"` // Keyed by unit, e.g. "USD" or "USD/shares"
+}
+
+// Fact is a single reported value of a concept
+type Fact struct {
+	Start Date    `json:"start"` // Zero for instant (point-in-time) facts
+	End   Date    `json:"end"`
+	Val   float64 `json:"val"`
+	Accn  string  `json:"accn"` // Accession number of the filing that reported the fact
+	FY    int     `json:"fy"`
+	FP    string  `json:"fp"` // Fiscal period: FY, Q1, Q2, Q3 or Q4
+	Form  string  `json:"form"`
+	Filed Date    `json:"filed"`
+	Frame string  `json:"frame,omitempty"` // Calendar frame, e.g. CY2023Q4I, set on the fact that best fits it
+}
+
+// GetCIKString returns the CIK as a string
+func (cf *CompanyFacts) GetCIKString() string {
+	return cikString(cf.CIK)
+}
+
+// cikString formats a CIK decoded from JSON, which the API returns as either a string or a number
+func cikString(cik interface{}) string {
+	switch v := cik.(type) {
+	case string:
+		return v
+	case json.Number:
+		return string(v)
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	case int:
+		return fmt.Sprintf("%d", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Taxonomy returns the concepts of the named taxonomy, e.g. "us-gaap"
+func (cf *CompanyFacts) Taxonomy(name string) (Taxonomy, bool) {
+	taxonomy, ok := cf.Facts[name]
+	return taxonomy, ok
+}
+
+// Concept returns the named concept, e.g. facts.Concept("us-gaap", "Revenues")
+func (cf *CompanyFacts) Concept(taxonomy, tag string) (*Concept, bool) {
+	concept, ok := cf.Facts[taxonomy][tag]
+	return concept, ok && concept != nil
+}
+
+// ReportingCurrency returns the currency most of the company's monetary facts are reported in, e.g. "USD"
+// or "EUR" for a foreign private issuer, or "" if it reported none
+func (cf *CompanyFacts) ReportingCurrency() string {
+	counts := make(map[string]int)
+	for _, taxonomy := range cf.Facts {
+		for _, concept := range taxonomy {
+			if concept == nil {
+				continue
+			}
+			for unit, facts := range concept.Units {
+				if isCurrency(unit) {
+					counts[unit] += len(facts)
+				}
+			}
+		}
+	}
+
+	var currency string
+	for unit, n := range counts {
+		if n > counts[currency] || n == counts[currency] && unit < currency {
+			currency = unit
+		}
+	}
+	return currency
+}
+
+// isCurrency reports whether unit is an ISO 4217 currency code such as "USD"
+func isCurrency(unit string) bool {
+	if len(unit) != 3 {
+		return false
+	}
+	for _, r := range unit {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Facts returns the facts reported in the given unit, e.g. "USD"
+func (c *Concept) Facts(unit string) []Fact {
+	return c.Units[unit]
+}
+
+// UnitNames returns the concept's units in sorted order
+func (c *Concept) UnitNames() []string {
+	names := make([]string, 0, len(c.Units))
+	for name := range c.Units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// currencyUnits returns the concept's units denominated in currency, e.g. "EUR" and "EUR/shares", with the
+// plain currency first
+func (c *Concept) currencyUnits(currency string) []string {
+	var units []string
+	for _, name := range c.UnitNames() {
+		if strings.Contains(strings.ToLower(name), strings.ToLower(currency)) {
+			units = append(units, name)
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool {
+		return units[i] == currency && units[j] != currency
+	})
+	return units
+}
+
+// IsInstant reports whether the fact is a point-in-time value (e.g. a balance sheet item)
+func (f Fact) IsInstant() bool {
+	return f.Start.IsZero()
+}
+
+// Days returns the length of the fact's period in days, or 0 for instant facts
+func (f Fact) Days() int {
+	if f.IsInstant() || f.End.IsZero() {
+		return 0
+	}
+	return int(f.End.Sub(f.Start.Time).Hours()/24) + 1
+}
@@ -0,0 +1,116 @@
+package edgar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate_JSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Date
+		output   string
+	}{
+		{
+			name:     "date",
+			input:    `"2023-12-30"`,
+			expected: NewDate(2023, time.December, 30),
+			output:   `"2023-12-30"`,
+		},
+		{
+			name:     "null",
+			input:    `null`,
+			expected: Date{},
+			output:   `null`,
+		},
+		{
+			name:     "empty string",
+			input:    `""`,
+			expected: Date{},
+			output:   `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &d))
+			assert.True(t, tt.expected.Equal(d.Time))
+
+			data, err := json.Marshal(d)
+			require.NoError(t, err)
+			assert.Equal(t, tt.output, string(data))
+		})
+	}
+
+	var d Date
+	assert.Error(t, json.Unmarshal([]byte(`"12/30/2023"`), &d))
+}
+
+func TestCompanyFacts_Decode(t *testing.T) {
+	var facts CompanyFacts
+	require.NoError(t, json.Unmarshal([]byte(getMockCompanyFacts()), &facts))
+
+	assert.Equal(t, "Apple Inc.", facts.Entity)
+
+	concept, ok := facts.Concept("us-gaap", "Revenues")
+	require.True(t, ok)
+	assert.Equal(t, []string{"USD"}, concept.UnitNames())
+
+	revenues := concept.Facts("USD")
+	require.Len(t, revenues, 1)
+	assert.Equal(t, 100000000000.0, revenues[0].Val)
+	assert.Equal(t, "10-Q", revenues[0].Form)
+	assert.Equal(t, "2023-12-30", revenues[0].End.String())
+	assert.Equal(t, "0000320193-24-000007", revenues[0].Accn)
+	assert.Equal(t, DurationQuarter, revenues[0].Duration())
+
+	_, ok = facts.Concept("us-gaap", "NonExistentMetric")
+	assert.False(t, ok)
+	_, ok = facts.Taxonomy("ifrs-full")
+	assert.False(t, ok)
+}
+
+func TestConcept_currencyUnits(t *testing.T) {
+	concept := &Concept{
+		Units: map[string][]Fact{
+			"shares":     nil,
+			"USD/shares": nil,
+			"USD":        nil,
+			"EUR/shares": nil,
+			"EUR":        nil,
+		},
+	}
+
+	assert.Equal(t, []string{"USD", "USD/shares"}, concept.currencyUnits("USD"))
+	assert.Equal(t, []string{"EUR", "EUR/shares"}, concept.currencyUnits("EUR"))
+	assert.Empty(t, concept.currencyUnits("JPY"))
+}
+
+func TestCompanyFacts_ReportingCurrency(t *testing.T) {
+	facts := &CompanyFacts{Facts: map[string]Taxonomy{
+		"dei": {"EntityPublicFloat": {Units: map[string][]Fact{"USD": make([]Fact, 1)}}},
+		"ifrs-full": {
+			"Revenue":                   {Units: map[string][]Fact{"EUR": make([]Fact, 3)}},
+			"BasicEarningsLossPerShare": {Units: map[string][]Fact{"EUR/shares": make([]Fact, 3)}},
+			"WeightedAverageShares":     {Units: map[string][]Fact{"shares": make([]Fact, 5)}},
+		},
+	}}
+	assert.Equal(t, "EUR", facts.ReportingCurrency())
+
+	assert.Empty(t, (&CompanyFacts{}).ReportingCurrency())
+}
+
+func TestFact_Days(t *testing.T) {
+	quarter := Fact{Start: NewDate(2023, time.October, 1), End: NewDate(2023, time.December, 30)}
+	assert.False(t, quarter.IsInstant())
+	assert.Equal(t, 91, quarter.Days())
+
+	instant := Fact{End: NewDate(2023, time.December, 30)}
+	assert.Equal(t, 0, instant.Days())
+}
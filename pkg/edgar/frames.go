@@ -0,0 +1,96 @@
+package edgar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Frame represents the frames API response: one concept reported by every filer for a calendar period
+type Frame struct {
+	Taxonomy    string      `json:"taxonomy"`
+	Tag         string      `json:"tag"`
+	Period      string      `json:"ccp"` // Calendar period, e.g. CY2023Q4I
+	Unit        string      `json:"uom"`
+	Label       string      `json:"label"`
+	Description string      `json:"description"`
+	Points      int         `json:"pts"` // Number of facts in Data
+	Data        []FrameFact `json:"data"`
+}
+
+// FrameFact is the value a single filer reported for the frame's period
+type FrameFact struct {
+	Accn       string  `json:"accn"`
+	CIK        int     `json:"cik"`
+	EntityName string  `json:"entityName"`
+	Location   string  `json:"loc"`   // e.g. US-CA
+	Start      Date    `json:"start"` // Zero for instant frames
+	End        Date    `json:"end"`
+	Val        float64 `json:"val"`
+}
+
+// CIKString returns the filer's CIK zero-padded to 10 digits, as used by the other endpoints
+func (f FrameFact) CIKString() string {
+	return fmt.Sprintf("%010d", f.CIK)
+}
+
+// CalendarYear returns the frame period for an annual duration, e.g. CY2023
+func CalendarYear(year int) string {
+	return fmt.Sprintf("CY%d", year)
+}
+
+// CalendarQuarter returns the frame period for a quarterly duration, e.g. CY2023Q4
+func CalendarQuarter(year, quarter int) string {
+	return fmt.Sprintf("CY%dQ%d", year, quarter)
+}
+
+// CalendarInstant returns the frame period for point-in-time values at the end of a quarter, e.g. CY2023Q4I
+func CalendarInstant(year, quarter int) string {
+	return CalendarQuarter(year, quarter) + "I"
+}
+
+// GetFrame retrieves one concept across every filer for a calendar period.
+// unit is the unit of measure as used in the URL, e.g. "USD" or "USD-per-shares",
+// and period is built with CalendarYear, CalendarQuarter or CalendarInstant.
+func (c *Client) GetFrame(ctx context.Context, taxonomy, tag, unit, period string) (*Frame, error) {
+	url := fmt.Sprintf("%s%s%s/%s/%s/%s.json", c.baseURL, PathFrames, taxonomy, tag, unit, period)
+
+	body, err := c.makeRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var frame Frame
+	if err := json.Unmarshal(body, &frame); err != nil {
+		return nil, &DecodeError{URL: url, Err: err}
+	}
+
+	return &frame, nil
+}
+
+// Top returns the n facts with the highest values, largest first (all facts if n <= 0)
+func (f *Frame) Top(n int) []FrameFact {
+	ranked := make([]FrameFact, len(f.Data))
+	copy(ranked, f.Data)
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Val > ranked[j].Val
+	})
+
+	if n > 0 && n < len(ranked) {
+		ranked = ranked[:n]
+	}
+
+	return ranked
+}
+
+// Rank returns the 1-based position of the filer with the given CIK when ordered by value, largest first
+func (f *Frame) Rank(cik int) (int, bool) {
+	for i, fact := range f.Top(0) {
+		if fact.CIK == cik {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,81 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock frames response for an instant period
+func getMockFrame() string {
+	return `{
+		"taxonomy": "us-gaap",
+		"tag": "AccountsPayableCurrent",
+		"ccp": "CY2023Q4I",
+		"uom": "USD",
+		"label": "Accounts Payable, Current",
+		"description": "Carrying value as of the balance sheet date of liabilities incurred.",
+		"pts": 3,
+		"data": [
+			{"accn": "0001104659-24-012345", "cik": 1750, "entityName": "AAR CORP.", "loc": "US-IL", "end": "2023-12-31", "val": 218600000},
+			{"accn": "0000320193-24-000006", "cik": 320193, "entityName": "Apple Inc.", "loc": "US-CA", "end": "2023-12-30", "val": 58146000000},
+			{"accn": "0000789019-24-000008", "cik": 789019, "entityName": "MICROSOFT CORPORATION", "loc": "US-WA", "end": "2023-12-31", "val": 19000000000}
+		]
+	}`
+}
+
+func TestClient_GetFrame(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(getMockFrame())) // Ignoring write error in test
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	frame, err := client.GetFrame(context.Background(), "us-gaap", "AccountsPayableCurrent", "USD", CalendarInstant(2023, 4))
+
+	require.NoError(t, err)
+	assert.Equal(t, "/api/xbrl/frames/us-gaap/AccountsPayableCurrent/USD/CY2023Q4I.json", path)
+	assert.Equal(t, "CY2023Q4I", frame.Period)
+	assert.Equal(t, "USD", frame.Unit)
+	assert.Equal(t, 3, frame.Points)
+	require.Len(t, frame.Data, 3)
+	assert.Equal(t, "0000001750", frame.Data[0].CIKString())
+	assert.Equal(t, "US-IL", frame.Data[0].Location)
+	assert.Equal(t, "2023-12-31", frame.Data[0].End.String())
+	assert.True(t, frame.Data[0].Start.IsZero())
+}
+
+func TestFramePeriods(t *testing.T) {
+	assert.Equal(t, "CY2023", CalendarYear(2023))
+	assert.Equal(t, "CY2023Q1", CalendarQuarter(2023, 1))
+	assert.Equal(t, "CY2023Q4I", CalendarInstant(2023, 4))
+}
+
+func TestFrame_Top(t *testing.T) {
+	server := createMockServer(getMockFrame(), http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	frame, err := client.GetFrame(context.Background(), "us-gaap", "AccountsPayableCurrent", "USD", CalendarInstant(2023, 4))
+	require.NoError(t, err)
+
+	top := frame.Top(2)
+	require.Len(t, top, 2)
+	assert.Equal(t, "Apple Inc.", top[0].EntityName)
+	assert.Equal(t, "MICROSOFT CORPORATION", top[1].EntityName)
+	assert.Len(t, frame.Top(0), 3)
+	assert.Equal(t, "AAR CORP.", frame.Data[0].EntityName, "Top must not reorder Data")
+
+	rank, ok := frame.Rank(789019)
+	assert.True(t, ok)
+	assert.Equal(t, 2, rank)
+
+	_, ok = frame.Rank(1)
+	assert.False(t, ok)
+}
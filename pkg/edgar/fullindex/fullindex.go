@@ -0,0 +1,267 @@
+// Package fullindex downloads and parses the EDGAR full-index and daily-index files,
+// which list every filing made in a quarter or on a day across all companies.
+package fullindex
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/natedogg/edgar/pkg/edgar"
+)
+
+// Kind selects which index file to read. All kinds list the same filings in a different order.
+type Kind string
+
+const (
+	Master  Kind = "master"  // Pipe-delimited, sorted by CIK
+	Form    Kind = "form"    // Fixed-width, sorted by form type
+	Company Kind = "company" // Fixed-width, sorted by company name
+)
+
+// Entry is a single filing listed in an index file
+type Entry struct {
+	CIK         string     `json:"cik"` // Without leading zeros, as listed in the index
+	CompanyName string     `json:"companyName"`
+	FormType    string     `json:"formType"`
+	DateFiled   edgar.Date `json:"dateFiled"`
+	Filename    string     `json:"filename"` // Path below Archives/, e.g. edgar/data/320193/0000320193-24-000006.txt
+}
+
+// AccessionNumber returns the accession number encoded in the entry's file name
+func (e Entry) AccessionNumber() string {
+	return strings.TrimSuffix(path.Base(e.Filename), ".txt")
+}
+
+// Filing returns the entry as an edgar.Filing with the fields the index provides
+func (e Entry) Filing() edgar.Filing {
+	return edgar.Filing{
+		AccessionNumber: e.AccessionNumber(),
+		FilingDate:      e.DateFiled,
+		Form:            e.FormType,
+	}
+}
+
+// Client downloads index files through an edgar.Client, sharing its User-Agent, rate limiter and retries
+type Client struct {
+	edgar      *edgar.Client
+	Compressed bool // Download the gzip variants of the index files
+}
+
+// New creates an index client that downloads through client
+func New(client *edgar.Client) *Client {
+	return &Client{edgar: client}
+}
+
+// QuarterPath returns the archives path of a quarterly index file, e.g.
+// /Archives/edgar/full-index/2024/QTR1/master.idx
+func QuarterPath(kind Kind, year, quarter int, compressed bool) string {
+	ext := ".idx"
+	if compressed {
+		ext = ".gz"
+	}
+	return fmt.Sprintf("/Archives/edgar/full-index/%d/QTR%d/%s%s", year, quarter, kind, ext)
+}
+
+// DailyPath returns the archives path of a daily index file, e.g.
+// /Archives/edgar/daily-index/2024/QTR1/master.20240102.idx
+func DailyPath(kind Kind, date time.Time, compressed bool) string {
+	ext := ".idx"
+	if compressed {
+		ext = ".idx.gz"
+	}
+	quarter := (int(date.Month())-1)/3 + 1
+	return fmt.Sprintf("/Archives/edgar/daily-index/%d/QTR%d/%s.%s%s", date.Year(), quarter, kind, date.Format("20060102"), ext)
+}
+
+// Quarter streams the entries of a quarterly index file
+func (c *Client) Quarter(ctx context.Context, kind Kind, year, quarter int) iter.Seq2[Entry, error] {
+	if quarter < 1 || quarter > 4 {
+		return func(yield func(Entry, error) bool) {
+			yield(Entry{}, fmt.Errorf("invalid quarter %d", quarter))
+		}
+	}
+	return c.stream(ctx, QuarterPath(kind, year, quarter, c.Compressed))
+}
+
+// Daily streams the entries of a daily index file. No file exists for weekends and holidays,
+// in which case the iterator yields an error matching edgar.ErrNotFound.
+func (c *Client) Daily(ctx context.Context, kind Kind, date time.Time) iter.Seq2[Entry, error] {
+	return c.stream(ctx, DailyPath(kind, date, c.Compressed))
+}
+
+// stream downloads an index file and yields its entries as they are read
+func (c *Client) stream(ctx context.Context, p string) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		body, err := c.edgar.OpenArchive(ctx, c.edgar.ArchivesURL()+p)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+		defer func() { _ = body.Close() }() // Ignoring close error
+
+		for entry, err := range Parse(body) {
+			if !yield(entry, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Forms filters entries to the given form types. Errors are passed through.
+func Forms(entries iter.Seq2[Entry, error], forms ...string) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		for entry, err := range entries {
+			if err != nil || slices.Contains(forms, entry.FormType) {
+				if !yield(entry, err) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Parse reads any kind of index file, gzip compressed or not, and yields its entries.
+// Iteration stops after the first error.
+func Parse(r io.Reader) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		br := bufio.NewReader(r)
+
+		// Gzip streams start with the magic bytes 1f 8b
+		if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+			gz, err := gzip.NewReader(br)
+			if err != nil {
+				yield(Entry{}, fmt.Errorf("error creating gzip reader: %w", err))
+				return
+			}
+			defer func() { _ = gz.Close() }() // Ignoring close error
+			br = bufio.NewReader(gz)
+		}
+
+		scanner := bufio.NewScanner(br)
+		var header string
+		var parse func(string) (Entry, error)
+		lineNumber := 0
+
+		for scanner.Scan() {
+			lineNumber++
+			line := scanner.Text()
+
+			if parse == nil {
+				// Entries start after the dashed line that follows the column header
+				if strings.HasPrefix(line, "---") {
+					p, err := newLineParser(header)
+					if err != nil {
+						yield(Entry{}, err)
+						return
+					}
+					parse = p
+				} else if strings.TrimSpace(line) != "" {
+					header = line
+				}
+				continue
+			}
+
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			entry, err := parse(line)
+			if err != nil {
+				yield(Entry{}, fmt.Errorf("line %d: %w", lineNumber, err))
+				return
+			}
+			if !yield(entry, nil) {
+				return
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			yield(Entry{}, fmt.Errorf("error reading index: %w", err))
+		}
+	}
+}
+
+// newLineParser returns a parser for entry lines based on the column header:
+// pipe-delimited for master files, fixed-width for form and company files
+func newLineParser(header string) (func(string) (Entry, error), error) {
+	if strings.Contains(header, "|") {
+		return parseMasterLine, nil
+	}
+
+	formAt := strings.Index(header, "Form Type")
+	companyAt := strings.Index(header, "Company Name")
+	if formAt < 0 || companyAt < 0 {
+		return nil, fmt.Errorf("unrecognized index header %q", header)
+	}
+
+	// The first two columns are fixed-width; CIK, date and file name never contain spaces
+	formFirst := formAt < companyAt
+	split := max(formAt, companyAt)
+
+	return func(line string) (Entry, error) {
+		rest, filename := cutLastField(line)
+		rest, date := cutLastField(rest)
+		rest, cik := cutLastField(rest)
+		if filename == "" || date == "" || cik == "" || len(rest) < split {
+			return Entry{}, fmt.Errorf("malformed entry %q", line)
+		}
+
+		first := strings.TrimSpace(rest[:split])
+		second := strings.TrimSpace(rest[split:])
+		entry := Entry{CIK: cik, Filename: filename, FormType: second, CompanyName: first}
+		if formFirst {
+			entry.FormType, entry.CompanyName = first, second
+		}
+
+		return withDate(entry, date)
+	}, nil
+}
+
+// parseMasterLine parses a "CIK|Company Name|Form Type|Date Filed|Filename" line
+func parseMasterLine(line string) (Entry, error) {
+	fields := strings.Split(line, "|")
+	if len(fields) != 5 {
+		return Entry{}, fmt.Errorf("malformed entry %q", line)
+	}
+
+	return withDate(Entry{
+		CIK:         strings.TrimSpace(fields[0]),
+		CompanyName: strings.TrimSpace(fields[1]),
+		FormType:    strings.TrimSpace(fields[2]),
+		Filename:    strings.TrimSpace(fields[4]),
+	}, strings.TrimSpace(fields[3]))
+}
+
+// withDate sets the entry's filing date, accepting both 2006-01-02 and the 20060102 form used by daily files
+func withDate(entry Entry, value string) (Entry, error) {
+	layout := edgar.DateLayout
+	if !strings.Contains(value, "-") {
+		layout = "20060102"
+	}
+
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return Entry{}, fmt.Errorf("invalid date %q: %w", value, err)
+	}
+
+	entry.DateFiled = edgar.Date{Time: t}
+	return entry, nil
+}
+
+// cutLastField splits off the last whitespace-separated field of s
+func cutLastField(s string) (rest, field string) {
+	s = strings.TrimRight(s, " \t")
+	i := strings.LastIndexAny(s, " \t")
+	if i < 0 {
+		return "", s
+	}
+	return s[:i], s[i+1:]
+}
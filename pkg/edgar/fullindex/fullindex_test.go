@@ -0,0 +1,217 @@
+package fullindex
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/natedogg/edgar/pkg/edgar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mockMasterIdx = `Description:           Master Index of EDGAR Dissemination Feed
+Last Data Received:    March 31, 2024
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+Cloud HTTP:            https://www.sec.gov/Archives/
+
+
+
+
+CIK|Company Name|Form Type|Date Filed|Filename
+--------------------------------------------------------------------------------
+1000045|NICHOLAS FINANCIAL INC|10-Q|2024-02-14|edgar/data/1000045/0000950170-24-015218.txt
+320193|Apple Inc.|10-Q|2024-02-02|edgar/data/320193/0000320193-24-000006.txt
+320193|Apple Inc.|SC 13G/A|2024-02-13|edgar/data/320193/0000315066-24-000123.txt
+`
+
+const mockFormIdx = `Description:           Daily Index of EDGAR Dissemination Feed by Form Type
+Last Data Received:    January 2, 2024
+Comments:              webmaster@sec.gov
+Anonymous FTP:         ftp://ftp.sec.gov/edgar/
+
+
+
+
+Form Type   Company Name                                                  CIK         Date Filed  File Name
+---------------------------------------------------------------------------------------------------------------------------------------------
+10-Q        Apple Inc.                                                    320193      20240102    edgar/data/320193/0000320193-24-000006.txt
+SC 13G/A    VANGUARD GROUP INC                                            102909      20240102    edgar/data/320193/0000315066-24-000123.txt
+`
+
+const mockCompanyIdx = `Description:           Master Index of EDGAR Dissemination Feed by Company Name
+
+
+
+
+Company Name                                                  Form Type   CIK         Date Filed  File Name
+---------------------------------------------------------------------------------------------------------------------------------------------
+Apple Inc.                                                    10-Q        320193      2024-02-02  edgar/data/320193/0000320193-24-000006.txt
+`
+
+// collect drains an iterator, failing the test on error
+func collect(t *testing.T, entries func(func(Entry, error) bool)) []Entry {
+	t.Helper()
+
+	var result []Entry
+	for entry, err := range entries {
+		require.NoError(t, err)
+		result = append(result, entry)
+	}
+	return result
+}
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{"master", []byte(mockMasterIdx)},
+		{"master gzip", gzipBytes(t, mockMasterIdx)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := collect(t, Parse(bytes.NewReader(tt.input)))
+
+			require.Len(t, entries, 3)
+			assert.Equal(t, Entry{
+				CIK:         "320193",
+				CompanyName: "Apple Inc.",
+				FormType:    "10-Q",
+				DateFiled:   edgar.NewDate(2024, time.February, 2),
+				Filename:    "edgar/data/320193/0000320193-24-000006.txt",
+			}, entries[1])
+			assert.Equal(t, "SC 13G/A", entries[2].FormType)
+		})
+	}
+}
+
+func TestParse_FixedWidth(t *testing.T) {
+	form := collect(t, Parse(strings.NewReader(mockFormIdx)))
+	require.Len(t, form, 2)
+	assert.Equal(t, "10-Q", form[0].FormType)
+	assert.Equal(t, "Apple Inc.", form[0].CompanyName)
+	assert.Equal(t, "2024-01-02", form[0].DateFiled.String())
+	assert.Equal(t, "SC 13G/A", form[1].FormType)
+	assert.Equal(t, "VANGUARD GROUP INC", form[1].CompanyName)
+	assert.Equal(t, "102909", form[1].CIK)
+
+	company := collect(t, Parse(strings.NewReader(mockCompanyIdx)))
+	require.Len(t, company, 1)
+	assert.Equal(t, "Apple Inc.", company[0].CompanyName)
+	assert.Equal(t, "10-Q", company[0].FormType)
+	assert.Equal(t, "320193", company[0].CIK)
+}
+
+func TestParse_MalformedLine(t *testing.T) {
+	input := "CIK|Company Name|Form Type|Date Filed|Filename\n----\n320193|Apple Inc.|10-Q\n"
+
+	var errs []error
+	for _, err := range Parse(strings.NewReader(input)) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "line 3")
+}
+
+func TestEntry_Filing(t *testing.T) {
+	entry := Entry{
+		CIK:       "320193",
+		FormType:  "10-Q",
+		DateFiled: edgar.NewDate(2024, time.February, 2),
+		Filename:  "edgar/data/320193/0000320193-24-000006.txt",
+	}
+
+	filing := entry.Filing()
+	assert.Equal(t, "0000320193-24-000006", filing.AccessionNumber)
+	assert.Equal(t, "10-Q", filing.Form)
+	assert.Equal(t, entry.DateFiled, filing.FilingDate)
+}
+
+func TestPaths(t *testing.T) {
+	assert.Equal(t, "/Archives/edgar/full-index/2024/QTR1/master.idx", QuarterPath(Master, 2024, 1, false))
+	assert.Equal(t, "/Archives/edgar/full-index/2024/QTR1/form.gz", QuarterPath(Form, 2024, 1, true))
+
+	date := time.Date(2024, time.August, 5, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "/Archives/edgar/daily-index/2024/QTR3/company.20240805.idx", DailyPath(Company, date, false))
+	assert.Equal(t, "/Archives/edgar/daily-index/2024/QTR3/master.20240805.idx.gz", DailyPath(Master, date, true))
+}
+
+func TestClient_Quarter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Archives/edgar/full-index/2024/QTR1/master.idx":
+			_, _ = w.Write([]byte(mockMasterIdx)) // Ignoring write error in test
+		case "/Archives/edgar/full-index/2024/QTR1/master.gz":
+			_, _ = w.Write(gzipBytes(t, mockMasterIdx)) // Ignoring write error in test
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := New(edgar.NewClient(edgar.WithArchivesURL(server.URL)))
+
+	entries := collect(t, Forms(client.Quarter(context.Background(), Master, 2024, 1), "10-Q"))
+	assert.Len(t, entries, 2)
+
+	client.Compressed = true
+	entries = collect(t, client.Quarter(context.Background(), Master, 2024, 1))
+	assert.Len(t, entries, 3)
+
+	for _, err := range client.Quarter(context.Background(), Master, 2024, 5) {
+		assert.Error(t, err)
+	}
+}
+
+func TestClient_Daily_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := New(edgar.NewClient(edgar.WithArchivesURL(server.URL)))
+	saturday := time.Date(2024, time.January, 6, 0, 0, 0, 0, time.UTC)
+
+	var errs []error
+	for _, err := range client.Daily(context.Background(), Master, saturday) {
+		errs = append(errs, err)
+	}
+
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], edgar.ErrNotFound)
+}
+
+func TestClient_Quarter_StopsEarly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(mockMasterIdx)) // Ignoring write error in test
+	}))
+	defer server.Close()
+
+	client := New(edgar.NewClient(edgar.WithArchivesURL(server.URL)))
+
+	count := 0
+	for _, err := range client.Quarter(context.Background(), Master, 2024, 1) {
+		require.NoError(t, err)
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
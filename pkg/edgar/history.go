@@ -0,0 +1,105 @@
+package edgar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// HistoryOptions limits the filings returned by GetFilingHistory. Zero values mean no limit.
+type HistoryOptions struct {
+	Since Date     // Only filings filed on or after this date; older pages are not downloaded
+	Forms []string // Only these form types, e.g. "10-Q", "10-K"
+	Limit int      // Stop once this many filings have been collected
+}
+
+// GetFilingHistory returns the company's complete filing history, newest first.
+// It merges Filings.Recent with every older page listed in Filings.Files,
+// downloading pages only until the options are satisfied.
+func (c *Client) GetFilingHistory(ctx context.Context, cik string, opts HistoryOptions) ([]Filing, error) {
+	return c.filingHistory(ctx, cik, opts.Since, opts.matches, opts.Limit)
+}
+
+// filingHistory returns the company's filings that match, newest first. Older pages are only downloaded
+// while they may hold filings filed on or after since, and until limit matching filings were collected.
+func (c *Client) filingHistory(ctx context.Context, cik string, since Date, match func(Filing) bool, limit int) ([]Filing, error) {
+	submissions, err := c.GetCompanySubmissions(ctx, cik)
+	if err != nil {
+		return nil, fmt.Errorf("error getting company submissions: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var history []Filing
+	collect := func(filings []Filing) {
+		for _, filing := range filings {
+			if seen[filing.AccessionNumber] || !match(filing) {
+				continue
+			}
+			seen[filing.AccessionNumber] = true
+			history = append(history, filing)
+		}
+	}
+
+	collect(c.parseFilings(submissions.Filings.Recent))
+
+	// Visit older pages newest first so Since and Limit can stop early
+	pages := slices.Clone(submissions.Filings.Files)
+	sort.SliceStable(pages, func(i, j int) bool {
+		return pages[i].FilingTo > pages[j].FilingTo
+	})
+
+	for _, page := range pages {
+		if limit > 0 && len(history) >= limit {
+			break
+		}
+		if !since.IsZero() && page.FilingTo < since.String() {
+			break
+		}
+
+		recent, err := c.getSubmissionsPage(ctx, page.Name)
+		if err != nil {
+			return nil, fmt.Errorf("error getting submissions page %s: %w", page.Name, err)
+		}
+		collect(c.parseFilings(recent))
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].FilingDate.After(history[j].FilingDate.Time)
+	})
+
+	if limit > 0 && len(history) > limit {
+		history = history[:limit]
+	}
+
+	return history, nil
+}
+
+// getSubmissionsPage retrieves an older submissions page, which has the same columns as Filings.Recent
+func (c *Client) getSubmissionsPage(ctx context.Context, name string) (map[string][]interface{}, error) {
+	url := fmt.Sprintf("%s%s%s", c.baseURL, PathSubmissions, name)
+
+	body, err := c.makeRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var recent map[string][]interface{}
+	if err := json.Unmarshal(body, &recent); err != nil {
+		return nil, &DecodeError{URL: url, Err: err}
+	}
+
+	return recent, nil
+}
+
+// matches reports whether a filing passes the Since and Forms filters
+func (o HistoryOptions) matches(filing Filing) bool {
+	if !o.Since.IsZero() && filing.FilingDate.Before(o.Since.Time) {
+		return false
+	}
+	if len(o.Forms) > 0 && !slices.Contains(o.Forms, filing.Form) {
+		return false
+	}
+	return true
+}
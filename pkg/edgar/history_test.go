@@ -0,0 +1,176 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock submissions response whose older filings are split across two pages
+func getMockPaginatedSubmissions() string {
+	return `{
+		"cik": "320193",
+		"name": "Apple Inc.",
+		"filings": {
+			"recent": {
+				"accessionNumber": ["0000320193-24-000007", "0000320193-23-000106"],
+				"filingDate": ["2024-02-01", "2023-11-03"],
+				"reportDate": ["2023-12-30", "2023-09-30"],
+				"form": ["10-Q", "10-K"],
+				"fileNumber": ["001-36743", "001-36743"],
+				"filmNumber": ["24576126", "231373899"],
+				"items": ["", ""],
+				"size": ["100000", "900000"],
+				"isXBRL": [1, 1],
+				"isInlineXBRL": [1, 1],
+				"primaryDocument": ["aapl-20231230.htm", "aapl-20230930.htm"],
+				"primaryDocDescription": ["10-Q", "10-K"]
+			},
+			"files": [
+				{"name": "CIK0000320193-submissions-002.json", "filingCount": 1, "filingFrom": "2014-01-01", "filingTo": "2015-12-31"},
+				{"name": "CIK0000320193-submissions-001.json", "filingCount": 2, "filingFrom": "2016-01-01", "filingTo": "2023-11-03"}
+			]
+		}
+	}`
+}
+
+// Mock older submissions pages, keyed by file name
+func getMockSubmissionsPages() map[string]string {
+	return map[string]string{
+		"CIK0000320193-submissions-001.json": `{
+			"accessionNumber": ["0000320193-23-000106", "0000320193-16-000070"],
+			"filingDate": ["2023-11-03", "2016-07-27"],
+			"reportDate": ["2023-09-30", "2016-06-25"],
+			"form": ["10-K", "10-Q"],
+			"fileNumber": ["001-36743", "001-36743"],
+			"filmNumber": ["231373899", "161786811"],
+			"items": ["", ""],
+			"size": ["900000", "100000"],
+			"isXBRL": [1, 1],
+			"isInlineXBRL": [1, 0],
+			"primaryDocument": ["aapl-20230930.htm", "a10-qq3201606252016.htm"],
+			"primaryDocDescription": ["10-K", "10-Q"]
+		}`,
+		"CIK0000320193-submissions-002.json": `{
+			"accessionNumber": ["0001193125-15-356351"],
+			"filingDate": ["2015-10-28"],
+			"reportDate": ["2015-09-26"],
+			"form": ["10-K"],
+			"fileNumber": ["001-36743"],
+			"filmNumber": ["151180619"],
+			"items": [""],
+			"size": ["900000"],
+			"isXBRL": [1],
+			"isInlineXBRL": [0],
+			"primaryDocument": ["d17062d10k.htm"],
+			"primaryDocDescription": ["10-K"]
+		}`,
+	}
+}
+
+// createSubmissionsServer serves paginated submissions and counts page downloads
+func createSubmissionsServer() (*httptest.Server, *int32) {
+	var pageRequests int32
+	pages := getMockSubmissionsPages()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == PathSubmissions+"CIK"+mockCIK+".json" {
+			_, _ = w.Write([]byte(getMockPaginatedSubmissions())) // Ignoring write error in test
+			return
+		}
+		for name, body := range pages {
+			if r.URL.Path == PathSubmissions+name {
+				atomic.AddInt32(&pageRequests, 1)
+				_, _ = w.Write([]byte(body)) // Ignoring write error in test
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	return server, &pageRequests
+}
+
+func TestClient_GetFilingHistory(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          HistoryOptions
+		expected      []string
+		expectedPages int32
+	}{
+		{
+			name: "all pages merged and deduplicated",
+			opts: HistoryOptions{},
+			expected: []string{
+				"0000320193-24-000007",
+				"0000320193-23-000106",
+				"0000320193-16-000070",
+				"0001193125-15-356351",
+			},
+			expectedPages: 2,
+		},
+		{
+			name:          "since stops before older pages",
+			opts:          HistoryOptions{Since: NewDate(2016, time.January, 1)},
+			expected:      []string{"0000320193-24-000007", "0000320193-23-000106", "0000320193-16-000070"},
+			expectedPages: 1,
+		},
+		{
+			name:          "forms",
+			opts:          HistoryOptions{Forms: []string{"10-K"}},
+			expected:      []string{"0000320193-23-000106", "0001193125-15-356351"},
+			expectedPages: 2,
+		},
+		{
+			name:          "limit satisfied by recent filings",
+			opts:          HistoryOptions{Limit: 2},
+			expected:      []string{"0000320193-24-000007", "0000320193-23-000106"},
+			expectedPages: 0,
+		},
+		{
+			name:          "limit with forms",
+			opts:          HistoryOptions{Forms: []string{"10-Q"}, Limit: 2},
+			expected:      []string{"0000320193-24-000007", "0000320193-16-000070"},
+			expectedPages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, pageRequests := createSubmissionsServer()
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			history, err := client.GetFilingHistory(context.Background(), mockCIK, tt.opts)
+			require.NoError(t, err)
+
+			var accessions []string
+			for _, filing := range history {
+				accessions = append(accessions, filing.AccessionNumber)
+			}
+			assert.Equal(t, tt.expected, accessions)
+			assert.Equal(t, tt.expectedPages, atomic.LoadInt32(pageRequests))
+		})
+	}
+}
+
+func TestClient_GetFilingHistory_PageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == PathSubmissions+"CIK"+mockCIK+".json" {
+			_, _ = w.Write([]byte(getMockPaginatedSubmissions())) // Ignoring write error in test
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	_, err := client.GetFilingHistory(context.Background(), mockCIK, HistoryOptions{})
+
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Contains(t, err.Error(), "CIK0000320193-submissions-001.json")
+}
@@ -4,7 +4,7 @@
 package edgar
 
 import (
-	"net/http"
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -29,7 +29,7 @@ func TestIntegration_GetCompanyFacts(t *testing.T) {
 	// Add delay to respect SEC rate limits
 	time.Sleep(100 * time.Millisecond)
 
-	facts, err := client.GetCompanyFacts(testCIK)
+	facts, err := client.GetCompanyFacts(context.Background(), testCIK)
 
 	require.NoError(t, err)
 	assert.NotNil(t, facts)
@@ -57,7 +57,7 @@ func TestIntegration_GetCompanySubmissions(t *testing.T) {
 	// Add delay to respect SEC rate limits
 	time.Sleep(100 * time.Millisecond)
 
-	submissions, err := client.GetCompanySubmissions(testCIK)
+	submissions, err := client.GetCompanySubmissions(context.Background(), testCIK)
 
 	require.NoError(t, err)
 	assert.NotNil(t, submissions)
@@ -84,7 +84,7 @@ func TestIntegration_GetMostRecent10Q(t *testing.T) {
 	// Add delay to respect SEC rate limits
 	time.Sleep(100 * time.Millisecond)
 
-	filing, err := client.GetMostRecent10Q(testCIK)
+	filing, err := client.GetMostRecent10Q(context.Background(), testCIK)
 
 	require.NoError(t, err)
 	assert.NotNil(t, filing)
@@ -94,8 +94,8 @@ func TestIntegration_GetMostRecent10Q(t *testing.T) {
 	assert.NotEmpty(t, filing.ReportDate)
 
 	// Verify date format (should be YYYY-MM-DD)
-	assert.Len(t, filing.FilingDate, 10)
-	assert.Len(t, filing.ReportDate, 10)
+	assert.Len(t, filing.FilingDate.String(), 10)
+	assert.Len(t, filing.ReportDate.String(), 10)
 }
 
 func TestIntegration_GetMostRecent4TenQs(t *testing.T) {
@@ -108,7 +108,7 @@ func TestIntegration_GetMostRecent4TenQs(t *testing.T) {
 	// Add delay to respect SEC rate limits
 	time.Sleep(100 * time.Millisecond)
 
-	filings, err := client.GetMostRecent4TenQs(testCIK)
+	filings, err := client.GetMostRecent4TenQs(context.Background(), testCIK)
 
 	require.NoError(t, err)
 	assert.NotNil(t, filings)
@@ -124,7 +124,7 @@ func TestIntegration_GetMostRecent4TenQs(t *testing.T) {
 
 	// Verify they are sorted by filing date (most recent first)
 	for i := 0; i < len(filings)-1; i++ {
-		assert.True(t, filings[i].FilingDate >= filings[i+1].FilingDate,
+		assert.False(t, filings[i].FilingDate.Before(filings[i+1].FilingDate.Time),
 			"filings should be sorted by date (most recent first)")
 	}
 }
@@ -140,26 +140,27 @@ func TestIntegration_ParseCashFlowMetrics(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	// Get the most recent 10-Q filing
-	filing, err := client.GetMostRecent10Q(testCIK)
+	filing, err := client.GetMostRecent10Q(context.Background(), testCIK)
 	require.NoError(t, err)
 
 	// Add delay before next API call
 	time.Sleep(100 * time.Millisecond)
 
 	// Parse cash flow metrics
-	metrics, err := client.ParseCashFlowMetrics(testCIK, filing)
+	metrics, err := client.ParseCashFlowMetrics(context.Background(), testCIK, filing)
 
 	require.NoError(t, err)
 	assert.NotNil(t, metrics)
 	assert.NotEmpty(t, metrics.CompanyName)
 	assert.Equal(t, "320193", metrics.CIK) // API returns CIK without leading zeros
-	assert.Equal(t, filing.FilingDate, metrics.FilingDate)
-	assert.Equal(t, filing.ReportDate, metrics.ReportDate)
+	assert.Equal(t, filing.FilingDate.String(), metrics.FilingDate)
+	assert.Equal(t, filing.ReportDate.String(), metrics.ReportDate)
 	assert.Equal(t, "10-Q", metrics.Form)
 
 	// Verify Free Cash Flow calculation
-	expectedFCF := metrics.NetCashFromOperatingActivities - metrics.CapitalExpenditures
-	assert.Equal(t, expectedFCF, metrics.FreeCashFlow)
+	require.NotNil(t, metrics.NetCashFromOperatingActivities)
+	expectedFCF := *metrics.NetCashFromOperatingActivities - valueOf(metrics.CapitalExpenditures)
+	assert.Equal(t, Float(expectedFCF), metrics.FreeCashFlow)
 }
 
 func TestIntegration_ParseEBITDAMetrics(t *testing.T) {
@@ -173,32 +174,34 @@ func TestIntegration_ParseEBITDAMetrics(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	// Get the most recent 10-Q filing
-	filing, err := client.GetMostRecent10Q(testCIK)
+	filing, err := client.GetMostRecent10Q(context.Background(), testCIK)
 	require.NoError(t, err)
 
 	// Add delay before next API call
 	time.Sleep(100 * time.Millisecond)
 
 	// Parse EBITDA metrics
-	metrics, err := client.ParseEBITDAMetrics(testCIK, filing)
+	metrics, err := client.ParseEBITDAMetrics(context.Background(), testCIK, filing)
 
 	require.NoError(t, err)
 	assert.NotNil(t, metrics)
 	assert.NotEmpty(t, metrics.CompanyName)
 	assert.Equal(t, "320193", metrics.CIK) // API returns CIK without leading zeros
-	assert.Equal(t, filing.FilingDate, metrics.FilingDate)
-	assert.Equal(t, filing.ReportDate, metrics.ReportDate)
+	assert.Equal(t, filing.FilingDate.String(), metrics.FilingDate)
+	assert.Equal(t, filing.ReportDate.String(), metrics.ReportDate)
 	assert.Equal(t, "10-Q", metrics.Form)
 
 	// Verify EBITDA calculation
-	expectedEBITDA := metrics.NetIncome + metrics.InterestExpense +
-		metrics.IncomeTaxExpense + metrics.DepreciationAndAmortization
-	assert.Equal(t, expectedEBITDA, metrics.EBITDA)
+	require.NotNil(t, metrics.NetIncome)
+	expectedEBITDA := *metrics.NetIncome + valueOf(metrics.InterestExpense) +
+		valueOf(metrics.IncomeTaxExpense) + valueOf(metrics.DepreciationAndAmortization)
+	assert.Equal(t, Float(expectedEBITDA), metrics.EBITDA)
 
 	// Verify EBITDA Margin calculation (if revenue is not zero)
-	if metrics.Revenue != 0 {
-		expectedMargin := (metrics.EBITDA / metrics.Revenue) * 100
-		assert.InDelta(t, expectedMargin, metrics.EBITDAMargin, 0.01)
+	if valueOf(metrics.Revenue) != 0 {
+		expectedMargin := (expectedEBITDA / *metrics.Revenue) * 100
+		require.NotNil(t, metrics.EBITDAMargin)
+		assert.InDelta(t, expectedMargin, *metrics.EBITDAMargin, 0.01)
 	}
 }
 
@@ -212,7 +215,7 @@ func TestIntegration_GetQuarterlyCashFlowAnalysis(t *testing.T) {
 	// Add delay to respect SEC rate limits
 	time.Sleep(100 * time.Millisecond)
 
-	analysis, err := client.GetQuarterlyCashFlowAnalysis(testCIK)
+	analysis, err := client.GetQuarterlyCashFlowAnalysis(context.Background(), testCIK)
 
 	require.NoError(t, err)
 	assert.NotNil(t, analysis)
@@ -225,11 +228,15 @@ func TestIntegration_GetQuarterlyCashFlowAnalysis(t *testing.T) {
 	for i, quarter := range analysis.Quarters {
 		assert.NotEmpty(t, quarter.FilingDate, "quarter %d should have filing date", i+1)
 		assert.NotEmpty(t, quarter.ReportDate, "quarter %d should have report date", i+1)
-		assert.Equal(t, "10-Q", quarter.Form, "quarter %d should be 10-Q form", i+1)
+		assert.Contains(t, QuarterlyForms, quarter.Form, "quarter %d should be a 10-Q or 10-K", i+1)
 
 		// Verify FCF calculation
-		expectedFCF := quarter.NetCashFromOperatingActivities - quarter.CapitalExpenditures
-		assert.Equal(t, expectedFCF, quarter.FreeCashFlow, "quarter %d FCF calculation", i+1)
+		if quarter.NetCashFromOperatingActivities == nil {
+			assert.Nil(t, quarter.FreeCashFlow, "quarter %d FCF without operating cash flow", i+1)
+			continue
+		}
+		expectedFCF := *quarter.NetCashFromOperatingActivities - valueOf(quarter.CapitalExpenditures)
+		assert.Equal(t, Float(expectedFCF), quarter.FreeCashFlow, "quarter %d FCF calculation", i+1)
 	}
 }
 
@@ -243,7 +250,7 @@ func TestIntegration_GetQuarterlyEBITDAAnalysis(t *testing.T) {
 	// Add delay to respect SEC rate limits
 	time.Sleep(100 * time.Millisecond)
 
-	analysis, err := client.GetQuarterlyEBITDAAnalysis(testCIK)
+	analysis, err := client.GetQuarterlyEBITDAAnalysis(context.Background(), testCIK)
 
 	require.NoError(t, err)
 	assert.NotNil(t, analysis)
@@ -256,17 +263,22 @@ func TestIntegration_GetQuarterlyEBITDAAnalysis(t *testing.T) {
 	for i, quarter := range analysis.Quarters {
 		assert.NotEmpty(t, quarter.FilingDate, "quarter %d should have filing date", i+1)
 		assert.NotEmpty(t, quarter.ReportDate, "quarter %d should have report date", i+1)
-		assert.Equal(t, "10-Q", quarter.Form, "quarter %d should be 10-Q form", i+1)
+		assert.Contains(t, QuarterlyForms, quarter.Form, "quarter %d should be a 10-Q or 10-K", i+1)
 
 		// Verify EBITDA calculation
-		expectedEBITDA := quarter.NetIncome + quarter.InterestExpense +
-			quarter.IncomeTaxExpense + quarter.DepreciationAndAmortization
-		assert.Equal(t, expectedEBITDA, quarter.EBITDA, "quarter %d EBITDA calculation", i+1)
+		if quarter.NetIncome == nil {
+			assert.Nil(t, quarter.EBITDA, "quarter %d EBITDA without net income", i+1)
+			continue
+		}
+		expectedEBITDA := *quarter.NetIncome + valueOf(quarter.InterestExpense) +
+			valueOf(quarter.IncomeTaxExpense) + valueOf(quarter.DepreciationAndAmortization)
+		assert.Equal(t, Float(expectedEBITDA), quarter.EBITDA, "quarter %d EBITDA calculation", i+1)
 
 		// Verify EBITDA Margin calculation (if revenue is not zero)
-		if quarter.Revenue != 0 {
-			expectedMargin := (quarter.EBITDA / quarter.Revenue) * 100
-			assert.InDelta(t, expectedMargin, quarter.EBITDAMargin, 0.01, "quarter %d EBITDA margin", i+1)
+		if valueOf(quarter.Revenue) != 0 {
+			expectedMargin := (expectedEBITDA / *quarter.Revenue) * 100
+			require.NotNil(t, quarter.EBITDAMargin, "quarter %d EBITDA margin", i+1)
+			assert.InDelta(t, expectedMargin, *quarter.EBITDAMargin, 0.01, "quarter %d EBITDA margin", i+1)
 		}
 	}
 }
@@ -283,7 +295,7 @@ func TestIntegration_RateLimiting(t *testing.T) {
 	start := time.Now()
 
 	for i := 0; i < 3; i++ {
-		_, err := client.GetCompanySubmissions(testCIK)
+		_, err := client.GetCompanySubmissions(context.Background(), testCIK)
 		require.NoError(t, err)
 
 		// Add delay between requests
@@ -305,10 +317,10 @@ func TestIntegration_InvalidCIK(t *testing.T) {
 	// Test with invalid CIK
 	invalidCIK := "9999999999"
 
-	_, err := client.GetCompanyFacts(invalidCIK)
+	_, err := client.GetCompanyFacts(context.Background(), invalidCIK)
 	assert.Error(t, err, "should return error for invalid CIK")
 
-	_, err = client.GetCompanySubmissions(invalidCIK)
+	_, err = client.GetCompanySubmissions(context.Background(), invalidCIK)
 	assert.Error(t, err, "should return error for invalid CIK")
 }
 
@@ -318,14 +330,9 @@ func TestIntegration_NetworkTimeout(t *testing.T) {
 	}
 
 	// Create client with very short timeout
-	client := &Client{
-		httpClient: &http.Client{
-			Timeout: time.Millisecond * 1, // 1ms timeout - should fail
-		},
-		userAgent: userAgent,
-	}
+	client := NewClient(WithTimeout(time.Millisecond * 1)) // 1ms timeout - should fail
 
-	_, err := client.GetCompanyFacts(testCIK)
+	_, err := client.GetCompanyFacts(context.Background(), testCIK)
 	assert.Error(t, err, "should timeout with very short timeout")
 }
 
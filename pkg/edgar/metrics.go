@@ -0,0 +1,201 @@
+package edgar
+
+import (
+	"fmt"
+	"log"
+	"slices"
+)
+
+// Float returns a pointer to v, for filling in metric values
+func Float(v float64) *float64 {
+	return &v
+}
+
+// valueOf returns the value of an optional metric, or 0 when it is missing
+func valueOf(v *float64) float64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// Derived totals, named by their JSON field
+const (
+	TotalFreeCashFlow = "freeCashFlow"
+	TotalEBITDA       = "ebitda"
+	TotalEBITDAMargin = "ebitdaMargin"
+)
+
+// MetricStatus reports where the components of a set of metrics came from and which were missing or derived.
+// Components are named by their JSON field, e.g. "capitalExpenditures".
+type MetricStatus struct {
+	Incomplete        bool              `json:"incomplete,omitempty"`        // Some components are missing, so totals computed from them are partial or absent
+	MissingComponents []string          `json:"missingComponents,omitempty"` // Components no fact was found for
+	Derivations       map[string]string `json:"derivations,omitempty"`       // Components computed from year-to-date facts, e.g. "9M to 2024-06-29 minus 6M to 2024-03-30"
+	MissingReasons    map[string]string `json:"missingReasons,omitempty"`    // Why a missing component could not be found or derived, when known
+
+	// IncompleteTotals lists, for each derived total computed with some inputs missing, the inputs that
+	// were counted as zero, e.g. {"freeCashFlow": ["capitalExpenditures"]}
+	IncompleteTotals map[string][]string `json:"incompleteTotals,omitempty"`
+
+	// Provenance lists the facts each component was taken from. Components summed from several
+	// tags (e.g. depreciation plus amortization) have one entry per fact.
+	Provenance map[string][]Provenance `json:"provenance,omitempty"`
+}
+
+// Provenance identifies the fact a metric component was taken from
+type Provenance struct {
+	Taxonomy        string  `json:"taxonomy"`
+	Tag             string  `json:"tag"`
+	Unit            string  `json:"unit"`
+	Start           Date    `json:"start"` // null for instants
+	End             Date    `json:"end"`
+	AccessionNumber string  `json:"accessionNumber"`
+	Form            string  `json:"form"`
+	Filed           Date    `json:"filed"`
+	Value           float64 `json:"value"`
+	Derivation      string  `json:"derivation,omitempty"`      // Set when the value was computed from year-to-date facts, or DerivationSubtracted
+	Sign            string  `json:"sign,omitempty"`            // Sign rule of the concept map applied to the reported value
+	FromOtherFiling bool    `json:"fromOtherFiling,omitempty"` // Set when the analyzed filing did not report the period
+	Override        string  `json:"override,omitempty"`        // Concept map override the concept came from, e.g. "SIC 6021"; empty for the defaults
+}
+
+// DerivationSubtracted is the Derivation of a fact subtracted from a total rather than added to it, such as
+// the prior year-to-date period of a trailing-twelve-month value
+const DerivationSubtracted = "subtracted"
+
+// newProvenance describes a fact reported for taxonomy/tag in unit
+func newProvenance(taxonomy, tag, unit string, fact Fact, derivation string) Provenance {
+	return Provenance{
+		Taxonomy:        taxonomy,
+		Tag:             tag,
+		Unit:            unit,
+		Start:           fact.Start,
+		End:             fact.End,
+		AccessionNumber: fact.Accn,
+		Form:            fact.Form,
+		Filed:           fact.Filed,
+		Value:           fact.Val,
+		Derivation:      derivation,
+	}
+}
+
+// String describes the source, e.g.
+// "us-gaap:Revenues (USD) 2023-10-01 to 2023-12-30 from 10-Q 0000320193-24-000007 filed 2024-02-01"
+func (p Provenance) String() string {
+	period := "as of " + p.End.String()
+	if !p.Start.IsZero() {
+		period = p.Start.String() + " to " + p.End.String()
+	}
+
+	s := fmt.Sprintf("%s:%s (%s) %s from %s %s filed %s", p.Taxonomy, p.Tag, p.Unit, period, p.Form, p.AccessionNumber, p.Filed)
+	if p.Derivation == DerivationSubtracted {
+		s += ", subtracted"
+	} else if p.Derivation != "" {
+		s += ", derived as " + p.Derivation
+	}
+	if p.Sign != SignAsReported {
+		s += ", sign " + p.Sign
+	}
+	if p.FromOtherFiling {
+		s += ", not reported by the analyzed filing"
+	}
+	if p.Override != "" {
+		s += ", " + p.Override + " override"
+	}
+	return s
+}
+
+// addMissing records that a component could not be found
+func (s *MetricStatus) addMissing(name string) {
+	s.Incomplete = true
+	s.MissingComponents = append(s.MissingComponents, name)
+}
+
+// addMissingReason records why a missing component could not be found or derived
+func (s *MetricStatus) addMissingReason(name, reason string) {
+	if s.MissingReasons == nil {
+		s.MissingReasons = make(map[string]string)
+	}
+	s.MissingReasons[name] = reason
+}
+
+// addDerivation records that a component was derived from year-to-date facts
+func (s *MetricStatus) addDerivation(name, derivation string) {
+	if s.Derivations == nil {
+		s.Derivations = make(map[string]string)
+	}
+	s.Derivations[name] = derivation
+}
+
+// addSource records a fact a component was taken from, along with its derivation if any
+func (s *MetricStatus) addSource(name string, source Provenance) {
+	if s.Provenance == nil {
+		s.Provenance = make(map[string][]Provenance)
+	}
+	s.Provenance[name] = append(s.Provenance[name], source)
+}
+
+// totalInput is an input of a derived total, named by its JSON field
+type totalInput struct {
+	name  string
+	value *float64
+}
+
+// checkTotal records the missing inputs of a derived total in IncompleteTotals
+func (s *MetricStatus) checkTotal(total string, inputs ...totalInput) {
+	delete(s.IncompleteTotals, total)
+	for _, input := range inputs {
+		if input.value != nil {
+			continue
+		}
+		if s.IncompleteTotals == nil {
+			s.IncompleteTotals = make(map[string][]string)
+		}
+		s.IncompleteTotals[total] = append(s.IncompleteTotals[total], input.name)
+	}
+}
+
+// freeCashFlow returns operating cash flow minus capital expenditures, recording missing capital
+// expenditures (counted as zero) in status. It requires operating cash flow.
+func freeCashFlow(operating, capex *float64, status *MetricStatus) *float64 {
+	if operating == nil {
+		delete(status.IncompleteTotals, TotalFreeCashFlow)
+		return nil
+	}
+	status.checkTotal(TotalFreeCashFlow, totalInput{MetricCapitalExpenditures, capex})
+	return Float(*operating - valueOf(capex))
+}
+
+// calculate computes free cash flow. It requires operating cash flow; missing capital expenditures
+// count as zero and are listed in IncompleteTotals.
+func (m *CashFlowMetrics) calculate() {
+	m.FreeCashFlow = freeCashFlow(m.NetCashFromOperatingActivities, m.CapitalExpenditures, &m.MetricStatus)
+}
+
+// calculate computes EBITDA and EBITDA margin. EBITDA requires net income; missing interest,
+// tax or depreciation count as zero and are listed in IncompleteTotals, for the margin as well.
+func (m *EBITDAMetrics) calculate() {
+	m.EBITDA, m.EBITDAMargin = nil, nil
+	delete(m.IncompleteTotals, TotalEBITDA)
+	delete(m.IncompleteTotals, TotalEBITDAMargin)
+	if m.NetIncome == nil {
+		return
+	}
+	m.EBITDA = Float(*m.NetIncome + valueOf(m.InterestExpense) + valueOf(m.IncomeTaxExpense) + valueOf(m.DepreciationAndAmortization))
+	m.checkTotal(TotalEBITDA,
+		totalInput{MetricInterestExpense, m.InterestExpense},
+		totalInput{MetricIncomeTaxExpense, m.IncomeTaxExpense},
+		totalInput{MetricDepreciationAndAmortization, m.DepreciationAndAmortization},
+	)
+
+	// Calculate EBITDA Margin (as percentage)
+	if valueOf(m.Revenue) == 0 {
+		log.Printf("Warning: Revenue is missing or zero, cannot calculate EBITDA margin")
+		return
+	}
+	m.EBITDAMargin = Float(*m.EBITDA / *m.Revenue * 100)
+	if missing, ok := m.IncompleteTotals[TotalEBITDA]; ok {
+		m.IncompleteTotals[TotalEBITDAMargin] = slices.Clone(missing)
+	}
+}
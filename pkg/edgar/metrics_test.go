@@ -0,0 +1,234 @@
+package edgar
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCashFlowMetrics_calculate(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics CashFlowMetrics
+		wantFCF *float64
+	}{
+		{
+			name:    "all components",
+			metrics: CashFlowMetrics{NetCashFromOperatingActivities: Float(50), CapitalExpenditures: Float(5)},
+			wantFCF: Float(45),
+		},
+		{
+			name:    "zero capex",
+			metrics: CashFlowMetrics{NetCashFromOperatingActivities: Float(50), CapitalExpenditures: Float(0)},
+			wantFCF: Float(50),
+		},
+		{
+			name:    "missing capex",
+			metrics: CashFlowMetrics{NetCashFromOperatingActivities: Float(50)},
+			wantFCF: Float(50),
+		},
+		{
+			name:    "missing operating cash flow",
+			metrics: CashFlowMetrics{CapitalExpenditures: Float(5)},
+			wantFCF: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.metrics.calculate()
+			assert.Equal(t, tt.wantFCF, tt.metrics.FreeCashFlow)
+		})
+	}
+}
+
+func TestEBITDAMetrics_calculate(t *testing.T) {
+	tests := []struct {
+		name       string
+		metrics    EBITDAMetrics
+		wantEBITDA *float64
+		wantMargin *float64
+	}{
+		{
+			name: "all components",
+			metrics: EBITDAMetrics{
+				Revenue: Float(200), NetIncome: Float(25), InterestExpense: Float(5),
+				IncomeTaxExpense: Float(10), DepreciationAndAmortization: Float(10),
+			},
+			wantEBITDA: Float(50),
+			wantMargin: Float(25),
+		},
+		{
+			name:       "missing interest expense",
+			metrics:    EBITDAMetrics{Revenue: Float(100), NetIncome: Float(25), IncomeTaxExpense: Float(5)},
+			wantEBITDA: Float(30),
+			wantMargin: Float(30),
+		},
+		{
+			name:       "missing revenue",
+			metrics:    EBITDAMetrics{NetIncome: Float(25)},
+			wantEBITDA: Float(25),
+			wantMargin: nil,
+		},
+		{
+			name:       "missing net income",
+			metrics:    EBITDAMetrics{Revenue: Float(100), InterestExpense: Float(5)},
+			wantEBITDA: nil,
+			wantMargin: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.metrics.calculate()
+			assert.Equal(t, tt.wantEBITDA, tt.metrics.EBITDA)
+			assert.Equal(t, tt.wantMargin, tt.metrics.EBITDAMargin)
+		})
+	}
+}
+
+func TestClient_ParseCashFlowMetricsFromFacts_MissingVersusZero(t *testing.T) {
+	client := NewClient()
+	q1 := NewDate(2023, time.December, 30)
+	filing := &Filing{AccessionNumber: "a", Form: "10-Q", ReportDate: q1}
+
+	operating := &Concept{Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 40, "10-Q", "2024-02-02"))}}}
+	zeroCapex := &Concept{Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 0, "10-Q", "2024-02-02"))}}}
+
+	t.Run("zero capex is reported", func(t *testing.T) {
+		facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {
+			"NetCashProvidedByUsedInOperatingActivities": operating,
+			"PaymentsToAcquirePropertyPlantAndEquipment": zeroCapex,
+		}}}
+
+		metrics, err := client.ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+
+		assert.Equal(t, Float(0), metrics.CapitalExpenditures)
+		assert.Equal(t, Float(40), metrics.FreeCashFlow)
+		assert.False(t, metrics.Incomplete)
+		assert.Empty(t, metrics.MissingComponents)
+		assert.Empty(t, metrics.IncompleteTotals)
+	})
+
+	t.Run("missing capex is flagged", func(t *testing.T) {
+		facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {
+			"NetCashProvidedByUsedInOperatingActivities": operating,
+		}}}
+
+		metrics, err := client.ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+
+		assert.Nil(t, metrics.CapitalExpenditures)
+		assert.Equal(t, Float(40), metrics.FreeCashFlow)
+		assert.True(t, metrics.Incomplete)
+		assert.Equal(t, []string{"capitalExpenditures"}, metrics.MissingComponents)
+
+		// Free cash flow counts the missing capex as zero, and says so
+		assert.Equal(t, map[string][]string{TotalFreeCashFlow: {MetricCapitalExpenditures}}, metrics.IncompleteTotals)
+
+		data, err := json.Marshal(metrics)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"capitalExpenditures":null`)
+		assert.Contains(t, string(data), `"incomplete":true`)
+		assert.Contains(t, string(data), `"incompleteTotals":{"freeCashFlow":["capitalExpenditures"]}`)
+	})
+
+	t.Run("no free cash flow without operating cash flow", func(t *testing.T) {
+		facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {
+			"PaymentsToAcquirePropertyPlantAndEquipment": zeroCapex,
+		}}}
+
+		metrics, err := client.ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+
+		assert.Nil(t, metrics.FreeCashFlow)
+		assert.Empty(t, metrics.IncompleteTotals)
+	})
+}
+
+func TestClient_ParseEBITDAMetricsFromFacts_Incomplete(t *testing.T) {
+	client := NewClient()
+	q1 := NewDate(2023, time.December, 30)
+
+	facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {
+		"Revenues":                {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 100, "10-Q", "2024-02-02"))}}},
+		"NetIncomeLoss":           {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 20, "10-Q", "2024-02-02"))}}},
+		"IncomeTaxExpenseBenefit": {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 5, "10-Q", "2024-02-02"))}}},
+		"Depreciation":            {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 3, "10-Q", "2024-02-02"))}}},
+	}}}
+
+	metrics, err := client.ParseEBITDAMetricsFromFacts(facts, &Filing{AccessionNumber: "a", Form: "10-Q", ReportDate: q1})
+	require.NoError(t, err)
+
+	assert.Nil(t, metrics.InterestExpense)
+	assert.Equal(t, Float(3), metrics.DepreciationAndAmortization)
+	assert.Equal(t, Float(28), metrics.EBITDA)
+	require.NotNil(t, metrics.EBITDAMargin)
+	assert.InDelta(t, 28.0, *metrics.EBITDAMargin, 1e-9)
+	assert.True(t, metrics.Incomplete)
+	assert.Equal(t, []string{"interestExpense"}, metrics.MissingComponents)
+	assert.Equal(t, map[string][]string{
+		TotalEBITDA:       {MetricInterestExpense},
+		TotalEBITDAMargin: {MetricInterestExpense},
+	}, metrics.IncompleteTotals)
+
+	// Every component found is traced to its tag and fact
+	assert.Len(t, metrics.Provenance, 4)
+	require.Len(t, metrics.Provenance["depreciationAndAmortization"], 1)
+	source := metrics.Provenance["depreciationAndAmortization"][0]
+	assert.Equal(t, "us-gaap", source.Taxonomy)
+	assert.Equal(t, "Depreciation", source.Tag)
+	assert.Equal(t, "USD", source.Unit)
+	assert.Equal(t, "2023-10-01", source.Start.String())
+	assert.Equal(t, "2023-12-30", source.End.String())
+	assert.Equal(t, "10-Q", source.Form)
+	assert.Equal(t, "2024-02-02", source.Filed.String())
+	assert.Equal(t, 3.0, source.Value)
+}
+
+func TestClient_ParseEBITDAMetricsFromFacts_SummedProvenance(t *testing.T) {
+	client := NewClient()
+	q1 := NewDate(2023, time.December, 30)
+
+	facts := &CompanyFacts{Facts: map[string]Taxonomy{"us-gaap": {
+		"NetIncomeLoss":             {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 20, "10-Q", "2024-02-02"))}}},
+		"DepreciationNonproduction": {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 3, "10-Q", "2024-02-02"))}}},
+		"Amortization":              {Units: map[string][]Fact{"USD": {filedBy("a", ytdFact(q1, 2, "10-Q", "2024-02-02"))}}},
+	}}}
+
+	metrics, err := client.ParseEBITDAMetricsFromFacts(facts, &Filing{AccessionNumber: "a", Form: "10-Q", ReportDate: q1})
+	require.NoError(t, err)
+
+	assert.Equal(t, Float(5), metrics.DepreciationAndAmortization)
+	sources := metrics.Provenance["depreciationAndAmortization"]
+	require.Len(t, sources, 2)
+	assert.Equal(t, "DepreciationNonproduction", sources[0].Tag)
+	assert.Equal(t, "Amortization", sources[1].Tag)
+}
+
+func TestProvenance_String(t *testing.T) {
+	source := Provenance{
+		Taxonomy:        "us-gaap",
+		Tag:             "PaymentsToAcquirePropertyPlantAndEquipment",
+		Unit:            "USD",
+		Start:           NewDate(2024, time.March, 31),
+		End:             NewDate(2024, time.June, 29),
+		AccessionNumber: "0000320193-24-000081",
+		Form:            "10-Q",
+		Filed:           NewDate(2024, time.August, 2),
+		Value:           2151000000,
+		Derivation:      "9M to 2024-06-29 minus 6M to 2024-03-30",
+	}
+
+	assert.Equal(t, "us-gaap:PaymentsToAcquirePropertyPlantAndEquipment (USD) 2024-03-31 to 2024-06-29 from 10-Q 0000320193-24-000081 filed 2024-08-02, derived as 9M to 2024-06-29 minus 6M to 2024-03-30", source.String())
+
+	instant := Provenance{Taxonomy: "us-gaap", Tag: "Assets", Unit: "USD", End: NewDate(2024, time.June, 29), AccessionNumber: "x", Form: "10-Q", Filed: NewDate(2024, time.August, 2)}
+	assert.Equal(t, "us-gaap:Assets (USD) as of 2024-06-29 from 10-Q x filed 2024-08-02", instant.String())
+
+	instant.Sign = SignNegate
+	assert.Equal(t, "us-gaap:Assets (USD) as of 2024-06-29 from 10-Q x filed 2024-08-02, sign negate", instant.String())
+}
@@ -0,0 +1,173 @@
+package edgar
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables read by WithEnv
+const (
+	EnvBaseURL     = "SEC_API_BASE_URL"
+	EnvArchivesURL = "SEC_ARCHIVES_BASE_URL"
+	EnvUserAgent   = "SEC_USER_AGENT"
+	EnvTimeout     = "SEC_HTTP_TIMEOUT"
+	EnvCacheDir    = "SEC_CACHE_DIR"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets the base URL of the EDGAR data API (default https://data.sec.gov)
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		if u != "" {
+			c.baseURL = strings.TrimRight(u, "/")
+		}
+	}
+}
+
+// WithArchivesURL sets the base URL of the host serving /Archives and /files (default https://www.sec.gov)
+func WithArchivesURL(u string) Option {
+	return func(c *Client) {
+		if u != "" {
+			c.archivesURL = strings.TrimRight(u, "/")
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+// The SEC fair-access policy requires it to identify the caller, e.g. "Sample Company admin@example.com".
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		if ua != "" {
+			c.userAgent = ua
+		}
+	}
+}
+
+// WithHTTPClient replaces the underlying HTTP client
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+// WithTransport sets the RoundTripper used by the underlying HTTP client
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Transport = rt
+		c.httpClient = &hc
+	}
+}
+
+// WithTimeout sets the overall timeout of each HTTP request. Downloads through OpenArchive only wait
+// this long for the response headers, as reading a large body can take longer.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = d
+		c.httpClient = &hc
+	}
+}
+
+// WithRateLimiter shares the given limiter with this client. Passing nil disables rate limiting.
+// By default all clients in a process share a limiter of DefaultRequestsPerSecond.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithRateLimit gives this client its own limiter allowing rate requests per second with the given burst
+func WithRateLimit(rate float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = NewRateLimiter(rate, burst)
+	}
+}
+
+// WithRetryPolicy enables retries of transient failures according to p
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = p
+	}
+}
+
+// WithCache stores responses in cache and revalidates them with conditional requests.
+// Use WithCacheTTL to serve entries without revalidation for a while.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL serves cached responses whose URL path starts with pathPrefix (e.g. PathCompanyFacts)
+// without revalidation for ttl. An empty prefix sets the default for all endpoints, which is zero
+// (always revalidate).
+func WithCacheTTL(pathPrefix string, ttl time.Duration) Option {
+	return func(c *Client) {
+		if c.cacheTTLs == nil {
+			c.cacheTTLs = make(map[string]time.Duration)
+		}
+		c.cacheTTLs[pathPrefix] = ttl
+	}
+}
+
+// WithBulkArchive serves GetCompanyFacts or GetCompanySubmissions (depending on the archive's kind)
+// from a local bulk archive instead of the network. Can be given once per kind; nil is ignored.
+func WithBulkArchive(archive *BulkArchive) Option {
+	return func(c *Client) {
+		if archive != nil {
+			c.bulk = append(c.bulk, archive)
+		}
+	}
+}
+
+// WithConceptMap sets the concepts metrics are read from, e.g. a map from LoadConceptMap.
+// nil keeps the embedded default map.
+func WithConceptMap(m *ConceptMap) Option {
+	return func(c *Client) {
+		if m != nil {
+			c.concepts = m
+		}
+	}
+}
+
+// WithFilingFallback lets metrics use the same period as reported (or restated) by another filing when the
+// analyzed filing reported no fact for it. Such components are marked fromOtherFiling in their provenance.
+// By default only the filing's own facts are used, and components it did not report are missing.
+func WithFilingFallback() Option {
+	return func(c *Client) {
+		c.fallback = true
+	}
+}
+
+// WithEnv applies any settings found in the SEC_* environment variables.
+// Unset or invalid variables leave the current configuration untouched.
+func WithEnv() Option {
+	return func(c *Client) {
+		WithBaseURL(os.Getenv(EnvBaseURL))(c)
+		WithArchivesURL(os.Getenv(EnvArchivesURL))(c)
+		WithUserAgent(os.Getenv(EnvUserAgent))(c)
+
+		if v := os.Getenv(EnvTimeout); v != "" {
+			if d, err := time.ParseDuration(v); err == nil {
+				WithTimeout(d)(c)
+			}
+		}
+
+		if dir := os.Getenv(EnvCacheDir); dir != "" {
+			cache, err := NewFileCache(dir)
+			if err != nil {
+				log.Printf("Warning: Could not use %s=%s: %v", EnvCacheDir, dir, err)
+			} else {
+				WithCache(cache)(c)
+			}
+		}
+	}
+}
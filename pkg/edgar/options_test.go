@@ -0,0 +1,111 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClient_Options(t *testing.T) {
+	transport := &recordingTransport{}
+
+	client := NewClient(
+		WithBaseURL("http://localhost:8080/"),
+		WithArchivesURL("http://localhost:9090"),
+		WithUserAgent("Example Corp ops@example.com"),
+		WithTimeout(5*time.Second),
+		WithTransport(transport),
+	)
+
+	assert.Equal(t, "http://localhost:8080", client.baseURL)
+	assert.Equal(t, "http://localhost:9090", client.archivesURL)
+	assert.Equal(t, "Example Corp ops@example.com", client.userAgent)
+	assert.Equal(t, 5*time.Second, client.httpClient.Timeout)
+	assert.Equal(t, transport, client.httpClient.Transport)
+}
+
+func TestNewClient_EmptyOptionsKeepDefaults(t *testing.T) {
+	client := NewClient(WithBaseURL(""), WithUserAgent(""), WithHTTPClient(nil))
+
+	assert.Equal(t, baseURL, client.baseURL)
+	assert.Equal(t, userAgent, client.userAgent)
+	assert.NotNil(t, client.httpClient)
+}
+
+func TestNewClient_WithHTTPClient(t *testing.T) {
+	hc := &http.Client{Timeout: time.Minute}
+
+	client := NewClient(WithHTTPClient(hc))
+
+	assert.Same(t, hc, client.httpClient)
+}
+
+func TestWithEnv(t *testing.T) {
+	t.Setenv(EnvBaseURL, "http://mirror.internal")
+	t.Setenv(EnvArchivesURL, "http://archives.internal")
+	t.Setenv(EnvUserAgent, "Env Corp env@example.com")
+	t.Setenv(EnvTimeout, "10s")
+	t.Setenv(EnvCacheDir, t.TempDir())
+
+	client := NewClient(WithEnv())
+
+	assert.Equal(t, "http://mirror.internal", client.baseURL)
+	assert.Equal(t, "http://archives.internal", client.archivesURL)
+	assert.Equal(t, "Env Corp env@example.com", client.userAgent)
+	assert.Equal(t, 10*time.Second, client.httpClient.Timeout)
+	assert.IsType(t, &FileCache{}, client.cache)
+}
+
+func TestClient_CheckUserAgent(t *testing.T) {
+	assert.ErrorIs(t, NewClient().CheckUserAgent(), ErrNoUserAgent)
+	assert.ErrorIs(t, NewClient(WithUserAgent("")).CheckUserAgent(), ErrNoUserAgent)
+	assert.NoError(t, NewClient(WithUserAgent("Example Corp ops@example.com")).CheckUserAgent())
+
+	t.Setenv(EnvUserAgent, "Env Corp env@example.com")
+	assert.NoError(t, NewClient(WithEnv()).CheckUserAgent())
+}
+
+func TestWithEnv_InvalidTimeoutIgnored(t *testing.T) {
+	t.Setenv(EnvTimeout, "soon")
+
+	client := NewClient(WithEnv())
+
+	assert.Equal(t, time.Second*30, client.httpClient.Timeout)
+}
+
+func TestClient_GetCompanyFacts_WithBaseURL(t *testing.T) {
+	server := createMockServer(getMockCompanyFacts(), http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithUserAgent("Test Suite test@example.com"))
+
+	facts, err := client.GetCompanyFacts(context.Background(), mockCIK)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Apple Inc.", facts.Entity)
+}
+
+func TestWithConceptMap(t *testing.T) {
+	m, err := LoadConceptMap(writeConceptMap(t, "mapping.yaml", "metrics:\n  revenue:\n    - tag: SalesRevenueNet\n"))
+	require.NoError(t, err)
+
+	client := NewClient(WithConceptMap(m))
+	assert.Equal(t, "SalesRevenueNet", client.conceptMap().Metrics[MetricRevenue][0].Tag)
+
+	// nil keeps the default map
+	client = NewClient(WithConceptMap(nil))
+	assert.Equal(t, "Revenues", client.conceptMap().Metrics[MetricRevenue][0].Tag)
+}
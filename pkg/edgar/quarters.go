@@ -0,0 +1,57 @@
+package edgar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// priorYearToDate maps each year-to-date duration to the one a quarter shorter, which is subtracted
+// from it to derive the last quarter
+var priorYearToDate = map[PeriodDuration]PeriodDuration{
+	DurationHalfYear:   DurationQuarter,
+	DurationNineMonths: DurationHalfYear,
+	DurationYear:       DurationNineMonths,
+}
+
+// discreteQuarter returns the 3-month fact of a duration concept for the quarter selected by sel
+// (whose Duration is ignored). Cash flow statements are reported year-to-date, so when no 3-month fact
+// exists the quarter is derived by subtracting the prior year-to-date fact of the same fiscal year:
+// 6M - 3M, 9M - 6M, and 12M - 9M for the fourth quarter, whose 12M value comes from the 10-K.
+// The returned description of the derivation is empty for reported quarters.
+func discreteQuarter(facts []Fact, sel FactSelector) (Fact, string, bool) {
+	sel.Duration = DurationQuarter
+	if quarter, ok := sel.Select(facts); ok {
+		return quarter, "", true
+	}
+
+	for _, duration := range []PeriodDuration{DurationHalfYear, DurationNineMonths, DurationYear} {
+		sel.Duration = duration
+		ytd, ok := sel.Select(facts)
+		if !ok {
+			continue
+		}
+
+		// The year-to-date period one quarter shorter, starting on the same day
+		prior, ok := latestFiled(facts, func(f Fact) bool {
+			return f.Start.Equal(ytd.Start.Time) && f.Duration() == priorYearToDate[duration]
+		})
+		if !ok {
+			continue
+		}
+
+		quarter := ytd
+		quarter.Start = Date{prior.End.AddDate(0, 0, 1)}
+		quarter.Val = ytd.Val - prior.Val
+		quarter.Frame = ""
+
+		return quarter, fmt.Sprintf("%s to %s minus %s to %s", ytd.Duration(), ytd.End, prior.Duration(), prior.End), true
+	}
+
+	return Fact{}, "", false
+}
+
+// additive reports whether values in unit can be subtracted to derive a quarter from year-to-date values.
+// Per-share amounts and weighted average share counts cannot.
+func additive(unit string) bool {
+	return unit != "shares" && !strings.Contains(unit, "/")
+}
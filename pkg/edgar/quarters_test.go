@@ -0,0 +1,130 @@
+package edgar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ytdFact builds a fact for the fiscal year starting 2023-10-01
+func ytdFact(end Date, val float64, form, filed string) Fact {
+	f, _ := ParseDate(filed)
+	return Fact{Start: NewDate(2023, time.October, 1), End: end, Val: val, Form: form, Filed: f}
+}
+
+// filedBy sets the accession number of the filing that reported f
+func filedBy(accn string, f Fact) Fact {
+	f.Accn = accn
+	return f
+}
+
+func TestDiscreteQuarter(t *testing.T) {
+	q1 := NewDate(2023, time.December, 30)
+	q2 := NewDate(2024, time.March, 30)
+	q3 := NewDate(2024, time.June, 29)
+	fy := NewDate(2024, time.September, 28)
+
+	facts := []Fact{
+		ytdFact(q1, 30, "10-Q", "2024-02-02"),
+		ytdFact(q2, 55, "10-Q", "2024-05-03"),
+		ytdFact(q3, 80, "10-Q", "2024-08-02"),
+		ytdFact(fy, 110, "10-K", "2024-11-01"),
+		{Start: NewDate(2024, time.March, 31), End: q3, Val: 26, Form: "10-Q", Filed: NewDate(2024, time.August, 2)},
+		{End: q2, Val: 999, Form: "10-Q"}, // Instant facts are ignored
+	}
+
+	tests := []struct {
+		name       string
+		facts      []Fact
+		end        Date
+		accession  string
+		wantOK     bool
+		wantVal    float64
+		wantStart  Date
+		derivation string
+	}{
+		{
+			name:      "first quarter is reported as 3M",
+			facts:     facts,
+			end:       q1,
+			wantOK:    true,
+			wantVal:   30,
+			wantStart: NewDate(2023, time.October, 1),
+		},
+		{
+			name:       "second quarter from 6M minus 3M",
+			facts:      facts,
+			end:        q2,
+			wantOK:     true,
+			wantVal:    25,
+			wantStart:  NewDate(2023, time.December, 31),
+			derivation: "6M to 2024-03-30 minus 3M to 2023-12-30",
+		},
+		{
+			name:      "reported 3M preferred over derivation",
+			facts:     facts,
+			end:       q3,
+			wantOK:    true,
+			wantVal:   26,
+			wantStart: NewDate(2024, time.March, 31),
+		},
+		{
+			name:       "fourth quarter from 10-K minus 9M",
+			facts:      facts,
+			end:        fy,
+			wantOK:     true,
+			wantVal:    30,
+			wantStart:  NewDate(2024, time.June, 30),
+			derivation: "12M to 2024-09-28 minus 9M to 2024-06-29",
+		},
+		{
+			name: "restated prior period uses latest filing",
+			facts: []Fact{
+				ytdFact(q1, 30, "10-Q", "2024-02-02"),
+				ytdFact(q1, 32, "10-Q", "2024-05-03"),
+				ytdFact(q2, 55, "10-Q", "2024-05-03"),
+			},
+			end:        q2,
+			wantOK:     true,
+			wantVal:    23,
+			wantStart:  NewDate(2023, time.December, 31),
+			derivation: "6M to 2024-03-30 minus 3M to 2023-12-30",
+		},
+		{
+			name:   "missing prior year-to-date",
+			facts:  []Fact{ytdFact(q3, 80, "10-Q", "2024-08-02")},
+			end:    q3,
+			wantOK: false,
+		},
+		{
+			name:   "no fact for the period",
+			facts:  facts,
+			end:    NewDate(2022, time.December, 31),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, derivation, ok := discreteQuarter(tt.facts, FactSelector{AccessionNumber: tt.accession, End: tt.end})
+
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, tt.wantVal, fact.Val)
+			assert.Equal(t, tt.wantStart.String(), fact.Start.String())
+			assert.Equal(t, tt.end.String(), fact.End.String())
+			assert.Equal(t, DurationQuarter, fact.Duration())
+			assert.Equal(t, tt.derivation, derivation)
+		})
+	}
+}
+
+func TestAdditive(t *testing.T) {
+	assert.True(t, additive("USD"))
+	assert.True(t, additive("EUR"))
+	assert.False(t, additive("USD/shares"))
+	assert.False(t, additive("shares"))
+}
@@ -0,0 +1,122 @@
+package edgar
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// SortOrder orders the filings returned by ListFilings
+type SortOrder int
+
+const (
+	NewestFirst SortOrder = iota // Most recent filing date first (default)
+	OldestFirst                  // Earliest filing date first
+)
+
+// FilingQuery selects filings for ListFilings. Zero values match everything.
+type FilingQuery struct {
+	Forms             []string // Form types, e.g. "10-Q", "8-K"
+	IncludeAmendments bool     // Also match amendments of Forms, e.g. "10-Q/A"
+	FiledFrom         Date     // Filing date on or after
+	FiledTo           Date     // Filing date on or before
+	ReportFrom        Date     // Report (period end) date on or after
+	ReportTo          Date     // Report (period end) date on or before
+	XBRLOnly          bool     // Only filings with XBRL financial data
+	Items             []string // 8-K item codes, e.g. "2.02"; a filing matches if it reports any of them
+	Limit             int      // Maximum number of filings returned
+	Sort              SortOrder
+	AllPages          bool // Also search the older submissions pages (see GetFilingHistory), newest first only until Limit filings match
+}
+
+// ListFilings returns the company's filings matching query. An empty result is not an error.
+func (c *Client) ListFilings(ctx context.Context, cik string, query FilingQuery) ([]Filing, error) {
+	var filings []Filing
+	if query.AllPages {
+		// The newest filings are on the first pages, so older pages are only needed until Limit
+		// filings match; sorting oldest first needs them all
+		limit := query.Limit
+		if query.Sort != NewestFirst {
+			limit = 0
+		}
+		history, err := c.filingHistory(ctx, cik, query.FiledFrom, query.matches, limit)
+		if err != nil {
+			return nil, err
+		}
+		filings = history
+	} else {
+		submissions, err := c.GetCompanySubmissions(ctx, cik)
+		if err != nil {
+			return nil, fmt.Errorf("error getting company submissions: %w", err)
+		}
+		filings = c.parseFilings(submissions.Filings.Recent)
+	}
+
+	var matched []Filing
+	for _, filing := range filings {
+		if query.matches(filing) {
+			matched = append(matched, filing)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if query.Sort == OldestFirst {
+			return matched[i].FilingDate.Before(matched[j].FilingDate.Time)
+		}
+		return matched[i].FilingDate.After(matched[j].FilingDate.Time)
+	})
+
+	if query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+
+	return matched, nil
+}
+
+// matches reports whether a filing passes every filter of the query
+func (q FilingQuery) matches(filing Filing) bool {
+	if len(q.Forms) > 0 && !q.matchesForm(filing.Form) {
+		return false
+	}
+	if !inDateRange(filing.FilingDate, q.FiledFrom, q.FiledTo) {
+		return false
+	}
+	if !inDateRange(filing.ReportDate, q.ReportFrom, q.ReportTo) {
+		return false
+	}
+	if q.XBRLOnly && !filing.IsXBRL {
+		return false
+	}
+	if len(q.Items) > 0 && !slices.ContainsFunc(filing.Items, func(item string) bool {
+		return slices.Contains(q.Items, item)
+	}) {
+		return false
+	}
+	return true
+}
+
+// matchesForm reports whether form is one of the query's forms, or an amendment of one if requested
+func (q FilingQuery) matchesForm(form string) bool {
+	for _, f := range q.Forms {
+		if form == f || (q.IncludeAmendments && form == f+"/A") {
+			return true
+		}
+	}
+	return false
+}
+
+// inDateRange reports whether date lies within the inclusive range; zero bounds are open.
+// A zero date never matches a bounded range.
+func inDateRange(date, from, to Date) bool {
+	if date.IsZero() {
+		return from.IsZero() && to.IsZero()
+	}
+	if !from.IsZero() && date.Before(from.Time) {
+		return false
+	}
+	if !to.IsZero() && date.After(to.Time) {
+		return false
+	}
+	return true
+}
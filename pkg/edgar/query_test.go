@@ -0,0 +1,164 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock submissions response with a mix of forms, amendments and 8-K items
+func getMockMixedSubmissions() string {
+	return `{
+		"cik": "320193",
+		"name": "Apple Inc.",
+		"filings": {
+			"recent": {
+				"accessionNumber": ["0000320193-24-000010", "0000320193-24-000009", "0000320193-24-000008", "0000320193-24-000007", "0000320193-23-000106", "0000320193-23-000077"],
+				"filingDate": ["2024-05-02", "2024-03-15", "2024-02-10", "2024-02-01", "2023-11-03", "2023-08-04"],
+				"reportDate": ["2024-05-02", "2023-12-30", "2024-02-10", "2023-12-30", "2023-09-30", "2023-07-01"],
+				"form": ["8-K", "10-Q/A", "8-K", "10-Q", "10-K", "10-Q"],
+				"fileNumber": ["001-36743", "001-36743", "001-36743", "001-36743", "001-36743", "001-36743"],
+				"filmNumber": ["1", "2", "3", "4", "5", "6"],
+				"items": ["2.02,9.01", "", "5.02", "", "", ""],
+				"size": ["1000", "1000", "1000", "1000", "1000", "1000"],
+				"isXBRL": [0, 1, 0, 1, 1, 1],
+				"isInlineXBRL": [0, 1, 0, 1, 1, 1],
+				"primaryDocument": ["a.htm", "b.htm", "c.htm", "d.htm", "e.htm", "f.htm"],
+				"primaryDocDescription": ["8-K", "10-Q/A", "8-K", "10-Q", "10-K", "10-Q"]
+			}
+		}
+	}`
+}
+
+func TestClient_ListFilings(t *testing.T) {
+	server := createMockServer(getMockMixedSubmissions(), http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+
+	tests := []struct {
+		name     string
+		query    FilingQuery
+		expected []string // Filing dates
+	}{
+		{
+			name:     "all filings newest first",
+			query:    FilingQuery{},
+			expected: []string{"2024-05-02", "2024-03-15", "2024-02-10", "2024-02-01", "2023-11-03", "2023-08-04"},
+		},
+		{
+			name:     "form without amendments",
+			query:    FilingQuery{Forms: []string{"10-Q"}},
+			expected: []string{"2024-02-01", "2023-08-04"},
+		},
+		{
+			name:     "form with amendments",
+			query:    FilingQuery{Forms: []string{"10-Q"}, IncludeAmendments: true},
+			expected: []string{"2024-03-15", "2024-02-01", "2023-08-04"},
+		},
+		{
+			name:     "filing date range",
+			query:    FilingQuery{FiledFrom: NewDate(2024, time.January, 1), FiledTo: NewDate(2024, time.March, 15)},
+			expected: []string{"2024-03-15", "2024-02-10", "2024-02-01"},
+		},
+		{
+			name:     "report date range",
+			query:    FilingQuery{ReportTo: NewDate(2023, time.September, 30)},
+			expected: []string{"2023-11-03", "2023-08-04"},
+		},
+		{
+			name:     "XBRL only",
+			query:    FilingQuery{XBRLOnly: true, Limit: 2},
+			expected: []string{"2024-03-15", "2024-02-01"},
+		},
+		{
+			name:     "8-K items",
+			query:    FilingQuery{Forms: []string{"8-K"}, Items: []string{"2.02"}},
+			expected: []string{"2024-05-02"},
+		},
+		{
+			name:     "oldest first with limit",
+			query:    FilingQuery{Forms: []string{"10-Q", "10-K"}, Sort: OldestFirst, Limit: 2},
+			expected: []string{"2023-08-04", "2023-11-03"},
+		},
+		{
+			name:     "no matches",
+			query:    FilingQuery{Forms: []string{"S-1"}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filings, err := client.ListFilings(context.Background(), mockCIK, tt.query)
+			require.NoError(t, err)
+
+			var dates []string
+			for _, filing := range filings {
+				dates = append(dates, filing.FilingDate.String())
+			}
+			assert.Equal(t, tt.expected, dates)
+		})
+	}
+}
+
+func TestClient_ListFilings_AllPages(t *testing.T) {
+	server, _ := createSubmissionsServer()
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	filings, err := client.ListFilings(context.Background(), mockCIK, FilingQuery{Forms: []string{"10-K"}, AllPages: true})
+
+	require.NoError(t, err)
+	require.Len(t, filings, 2)
+	assert.Equal(t, "0001193125-15-356351", filings[1].AccessionNumber)
+}
+
+func TestClient_ListFilings_AllPagesLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     FilingQuery
+		wantAccn  string
+		wantPages int32
+	}{
+		{
+			name:      "newest first stops once the limit is reached",
+			query:     FilingQuery{Forms: []string{"10-K"}, Limit: 1, AllPages: true},
+			wantAccn:  "0000320193-23-000106",
+			wantPages: 0,
+		},
+		{
+			name:      "forms filter the filings counted towards the limit",
+			query:     FilingQuery{Forms: []string{"10-Q"}, Limit: 2, AllPages: true},
+			wantAccn:  "0000320193-24-000007",
+			wantPages: 1,
+		},
+		{
+			name:      "oldest first needs every page",
+			query:     FilingQuery{Forms: []string{"10-K"}, Limit: 1, Sort: OldestFirst, AllPages: true},
+			wantAccn:  "0001193125-15-356351",
+			wantPages: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, pages := createSubmissionsServer()
+			defer server.Close()
+
+			client := NewClient(WithBaseURL(server.URL))
+			filings, err := client.ListFilings(context.Background(), mockCIK, tt.query)
+
+			require.NoError(t, err)
+			require.NotEmpty(t, filings)
+			assert.LessOrEqual(t, len(filings), tt.query.Limit)
+			assert.Equal(t, tt.wantAccn, filings[0].AccessionNumber)
+			assert.Equal(t, tt.wantPages, atomic.LoadInt32(pages))
+		})
+	}
+}
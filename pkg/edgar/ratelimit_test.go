@@ -0,0 +1,105 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := NewRateLimiter(1, 5)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "burst requests should not wait")
+}
+
+func TestRateLimiter_Throttles(t *testing.T) {
+	limiter := NewRateLimiter(50, 1)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// 1 immediate request + 5 more at 50/s = at least 100ms
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiter_Concurrent(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 11; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, limiter.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiter_ContextCanceled(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRateLimiter_NilAndUnlimited(t *testing.T) {
+	var limiter *RateLimiter
+	assert.NoError(t, limiter.Wait(context.Background()))
+
+	unlimited := NewRateLimiter(0, 1)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, unlimited.Wait(context.Background()))
+	}
+}
+
+func TestClient_SharedRateLimiter(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = w.Write([]byte(`{}`)) // Ignoring write error in test
+	}))
+	defer server.Close()
+
+	shared := NewRateLimiter(50, 1)
+	first := NewClient(WithRateLimiter(shared))
+	second := NewClient(WithRateLimiter(shared))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := first.makeRequest(context.Background(), server.URL)
+		require.NoError(t, err)
+		_, err = second.makeRequest(context.Background(), server.URL)
+		require.NoError(t, err)
+	}
+
+	// 6 requests through one limiter at 50/s with burst 1 = at least 100ms
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&hits))
+}
+
+func TestNewClient_DefaultRateLimiterShared(t *testing.T) {
+	assert.Same(t, NewClient().limiter, NewClient().limiter)
+	assert.Nil(t, NewClient(WithRateLimiter(nil)).limiter)
+}
@@ -0,0 +1,137 @@
+package edgar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how the client retries transient failures.
+// Only idempotent failures are retried: HTTP 429, 500, 502, 503, 504 and dropped connections.
+// The zero value disables retries.
+type RetryPolicy struct {
+	MaxAttempts    int              // Total attempts including the first; values below 2 disable retries
+	InitialBackoff time.Duration    // Delay before the first retry
+	MaxBackoff     time.Duration    // Upper bound on the exponential backoff (Retry-After is honored as sent)
+	Jitter         float64          // Fraction of each delay that is randomized, between 0 and 1
+	OnRetry        func(RetryEvent) // Optional hook called before each retry, e.g. for metrics
+}
+
+// RetryEvent describes a failed attempt that is about to be retried
+type RetryEvent struct {
+	URL     string
+	Attempt int // The attempt that failed, starting at 1
+	Err     error
+	Delay   time.Duration // How long the client waits before the next attempt
+}
+
+// DefaultRetryPolicy returns a policy suitable for the SEC endpoints:
+// up to 4 attempts with exponential backoff starting at 500ms and capped at 10s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Jitter:         0.2,
+	}
+}
+
+// shouldRetry reports whether a failed attempt should be retried and how long to wait first
+func (p RetryPolicy) shouldRetry(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+		return 0, false
+	}
+
+	var he *HTTPError
+	if errors.As(err, &he) && he.RetryAfter > 0 {
+		return he.RetryAfter, true
+	}
+
+	return p.backoff(attempt), true
+}
+
+// backoff returns the jittered exponential delay before retrying the given attempt
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(float64(delay) * jitter * rand.Float64())
+	}
+
+	return delay
+}
+
+// isRetryable reports whether err is a transient failure worth retrying
+func isRetryable(err error) bool {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		switch he.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
+
+// sleepContext waits for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,197 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFlakyServer fails the first failures requests with statusCode, then returns response
+func createFlakyServer(failures int32, statusCode int, retryAfter, response string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCode)
+			return
+		}
+		_, _ = w.Write([]byte(response)) // Ignoring write error in test
+	}))
+	return server, &calls
+}
+
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestClient_Retry_TransientStatus(t *testing.T) {
+	for _, status := range []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			server, calls := createFlakyServer(2, status, "", `{"ok": true}`)
+			defer server.Close()
+
+			client := NewClient(WithRetryPolicy(fastRetryPolicy()), WithRateLimiter(nil))
+			body, err := client.makeRequest(context.Background(), server.URL)
+
+			require.NoError(t, err)
+			assert.Equal(t, `{"ok": true}`, string(body))
+			assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+			assert.Equal(t, Stats{Requests: 3, Retries: 2}, client.Stats())
+		})
+	}
+}
+
+func TestClient_Retry_NotRetryable(t *testing.T) {
+	server, calls := createFlakyServer(5, http.StatusNotFound, "", `{}`)
+	defer server.Close()
+
+	client := NewClient(WithRetryPolicy(fastRetryPolicy()), WithRateLimiter(nil))
+	_, err := client.makeRequest(context.Background(), server.URL)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 404")
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	assert.Equal(t, uint64(0), client.Stats().Retries)
+}
+
+func TestClient_Retry_ExhaustsAttempts(t *testing.T) {
+	server, calls := createFlakyServer(10, http.StatusServiceUnavailable, "", `{}`)
+	defer server.Close()
+
+	var events []RetryEvent
+	policy := fastRetryPolicy()
+	policy.OnRetry = func(e RetryEvent) { events = append(events, e) }
+
+	client := NewClient(WithRetryPolicy(policy), WithRateLimiter(nil))
+	_, err := client.makeRequest(context.Background(), server.URL)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 503")
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+	require.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].Attempt)
+	assert.Equal(t, 2, events[1].Attempt)
+	assert.Equal(t, server.URL, events[0].URL)
+}
+
+func TestClient_Retry_DisabledByDefault(t *testing.T) {
+	server, calls := createFlakyServer(1, http.StatusServiceUnavailable, "", `{}`)
+	defer server.Close()
+
+	client := NewClient()
+	_, err := client.makeRequest(context.Background(), server.URL)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestClient_Retry_HonorsRetryAfter(t *testing.T) {
+	server, _ := createFlakyServer(1, http.StatusTooManyRequests, "1", `{}`)
+	defer server.Close()
+
+	var delay time.Duration
+	policy := fastRetryPolicy()
+	policy.OnRetry = func(e RetryEvent) { delay = e.Delay }
+
+	client := NewClient(WithRetryPolicy(policy), WithRateLimiter(nil))
+	start := time.Now()
+	_, err := client.makeRequest(context.Background(), server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestClient_Retry_ContextCanceledDuringBackoff(t *testing.T) {
+	server, calls := createFlakyServer(10, http.StatusServiceUnavailable, "30", `{}`)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := NewClient(WithRetryPolicy(fastRetryPolicy()), WithRateLimiter(nil))
+	_, err := client.makeRequest(ctx, server.URL)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestClient_Retry_ConnectionDropped(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if !assert.NoError(t, err) {
+				return
+			}
+			_ = conn.Close() // Drop the connection without a response
+			return
+		}
+		_, _ = w.Write([]byte(`{}`)) // Ignoring write error in test
+	}))
+	defer server.Close()
+
+	client := NewClient(WithRetryPolicy(fastRetryPolicy()), WithRateLimiter(nil))
+	_, err := client.makeRequest(context.Background(), server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(5))
+	assert.Equal(t, time.Second, policy.backoff(50))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(2)
+		assert.GreaterOrEqual(t, delay, 100*time.Millisecond)
+		assert.LessOrEqual(t, delay, 200*time.Millisecond)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"negative seconds", "-5", 0},
+		{"http date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"garbage", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseRetryAfter(tt.value, now))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package edgar
+
+// FactSelector picks the fact a filing reported for a concept. Facts are matched on their period,
+// and only facts from the selected filing are accepted unless Fallback allows the same period as
+// reported (or restated) by other filings.
+type FactSelector struct {
+	AccessionNumber string         // Filing whose facts are selected, e.g. "0000320193-24-000006"; empty accepts any
+	End             Date           // Period end date, usually the filing's report date; zero matches any
+	Duration        PeriodDuration // Period length, e.g. DurationQuarter or DurationInstant; DurationAny matches any
+	Fallback        bool           // Accept another filing's fact when the selected filing reported none for the period
+}
+
+// ForFiling returns a selector for facts of the given duration reported by filing for its report date
+func ForFiling(filing *Filing, duration PeriodDuration) FactSelector {
+	return FactSelector{
+		AccessionNumber: filing.AccessionNumber,
+		End:             filing.ReportDate,
+		Duration:        duration,
+	}
+}
+
+// forFiling returns ForFiling(filing, duration), falling back to other filings if the client was
+// configured WithFilingFallback
+func (c *Client) forFiling(filing *Filing, duration PeriodDuration) FactSelector {
+	sel := ForFiling(filing, duration)
+	sel.Fallback = c.fallback
+	return sel
+}
+
+// Matches reports whether the fact covers the selected period, regardless of the filing it came from
+func (s FactSelector) Matches(f Fact) bool {
+	if !s.End.IsZero() && !f.End.Equal(s.End.Time) {
+		return false
+	}
+	return s.Duration == DurationAny || f.Duration() == s.Duration
+}
+
+// Select returns the fact covering the selected period, taken from the selected filing. With Fallback,
+// or when no filing is selected, it is otherwise taken from the most recent filing that reported the
+// period; use FromOtherFiling to tell such comparatives apart. Ties go to the most recently filed fact.
+func (s FactSelector) Select(facts []Fact) (Fact, bool) {
+	if s.AccessionNumber != "" {
+		if fact, ok := latestFiled(facts, func(f Fact) bool {
+			return f.Accn == s.AccessionNumber && s.Matches(f)
+		}); ok || !s.Fallback {
+			return fact, ok
+		}
+	}
+	return latestFiled(facts, s.Matches)
+}
+
+// FromOtherFiling reports whether a selected fact came from another filing than the selected one
+func (s FactSelector) FromOtherFiling(f Fact) bool {
+	return s.AccessionNumber != "" && f.Accn != s.AccessionNumber
+}
+
+// latestFiled returns the most recently filed fact for which keep returns true
+func latestFiled(facts []Fact, keep func(Fact) bool) (Fact, bool) {
+	var latest Fact
+	found := false
+	for _, fact := range facts {
+		if !keep(fact) {
+			continue
+		}
+		if !found || fact.Filed.After(latest.Filed.Time) {
+			latest = fact
+			found = true
+		}
+	}
+	return latest, found
+}
@@ -0,0 +1,146 @@
+package edgar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactSelector_Select(t *testing.T) {
+	q1 := NewDate(2023, time.December, 30)
+	facts := []Fact{
+		// Quarter and year-to-date values from the Q1 10-Q
+		{Start: NewDate(2023, time.October, 1), End: q1, Val: 100, Accn: "q1", Form: "10-Q", Filed: NewDate(2024, time.February, 2)},
+		// The same quarter restated as a comparative in the following year's 10-Q
+		{Start: NewDate(2023, time.October, 1), End: q1, Val: 105, Accn: "q1-next", Form: "10-Q", Filed: NewDate(2025, time.January, 31)},
+		// Trailing twelve months ending on the same date
+		{Start: NewDate(2023, time.January, 1), End: q1, Val: 400, Accn: "q1", Form: "10-Q", Filed: NewDate(2024, time.February, 2)},
+		// Balance at the end of the quarter
+		{End: q1, Val: 7, Accn: "q1", Form: "10-Q", Filed: NewDate(2024, time.February, 2)},
+		// Annual value from the 10-K
+		{Start: NewDate(2022, time.September, 25), End: NewDate(2023, time.September, 30), Val: 380, Accn: "fy", Form: "10-K", Filed: NewDate(2023, time.November, 3)},
+	}
+
+	tests := []struct {
+		name     string
+		selector FactSelector
+		wantOK   bool
+		wantVal  float64
+		wantAccn string
+	}{
+		{
+			name:     "quarter from the selected filing",
+			selector: FactSelector{AccessionNumber: "q1", End: q1, Duration: DurationQuarter},
+			wantOK:   true,
+			wantVal:  100,
+			wantAccn: "q1",
+		},
+		{
+			name:     "quarter from the latest filing when none is selected",
+			selector: FactSelector{End: q1, Duration: DurationQuarter},
+			wantOK:   true,
+			wantVal:  105,
+			wantAccn: "q1-next",
+		},
+		{
+			name:     "no quarter when the selected filing lacks it",
+			selector: FactSelector{AccessionNumber: "other", End: q1, Duration: DurationQuarter},
+			wantOK:   false,
+		},
+		{
+			name:     "quarter from another filing with fallback",
+			selector: FactSelector{AccessionNumber: "other", End: q1, Duration: DurationQuarter, Fallback: true},
+			wantOK:   true,
+			wantVal:  105,
+			wantAccn: "q1-next",
+		},
+		{
+			name:     "own quarter preferred with fallback",
+			selector: FactSelector{AccessionNumber: "q1", End: q1, Duration: DurationQuarter, Fallback: true},
+			wantOK:   true,
+			wantVal:  100,
+			wantAccn: "q1",
+		},
+		{
+			name:     "twelve months ending on the same date",
+			selector: FactSelector{AccessionNumber: "q1", End: q1, Duration: DurationYear},
+			wantOK:   true,
+			wantVal:  400,
+			wantAccn: "q1",
+		},
+		{
+			name:     "instant",
+			selector: FactSelector{AccessionNumber: "q1", End: q1, Duration: DurationInstant},
+			wantOK:   true,
+			wantVal:  7,
+			wantAccn: "q1",
+		},
+		{
+			name:     "annual",
+			selector: FactSelector{AccessionNumber: "fy", End: NewDate(2023, time.September, 30), Duration: DurationYear},
+			wantOK:   true,
+			wantVal:  380,
+			wantAccn: "fy",
+		},
+		{
+			name:     "no fact for the period",
+			selector: FactSelector{AccessionNumber: "q1", End: q1, Duration: DurationHalfYear},
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fact, ok := tt.selector.Select(facts)
+
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantVal, fact.Val)
+				assert.Equal(t, tt.wantAccn, fact.Accn)
+			}
+		})
+	}
+}
+
+func TestForFiling(t *testing.T) {
+	filing := &Filing{AccessionNumber: "0000320193-24-000006", ReportDate: NewDate(2023, time.December, 30)}
+
+	sel := ForFiling(filing, DurationQuarter)
+	assert.Equal(t, "0000320193-24-000006", sel.AccessionNumber)
+	assert.Equal(t, "2023-12-30", sel.End.String())
+	assert.Equal(t, DurationQuarter, sel.Duration)
+}
+
+func TestClient_FilingFallback(t *testing.T) {
+	q1 := NewDate(2023, time.December, 30)
+	filing := &Filing{AccessionNumber: "q1", Form: "10-Q", ReportDate: q1}
+
+	// The filing reported operating cash flow; capital expenditures only appear as a comparative a year later
+	facts := &CompanyFacts{CIK: "320193", Facts: map[string]Taxonomy{"us-gaap": {
+		"NetCashProvidedByUsedInOperatingActivities": {Units: map[string][]Fact{"USD": {filedBy("q1", ytdFact(q1, 40, "10-Q", "2024-02-02"))}}},
+		"PaymentsToAcquirePropertyPlantAndEquipment": {Units: map[string][]Fact{"USD": {filedBy("q1-next", ytdFact(q1, 5, "10-Q", "2025-01-31"))}}},
+	}}}
+
+	t.Run("own facts only by default", func(t *testing.T) {
+		metrics, err := NewClient().ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+		assert.Nil(t, metrics.CapitalExpenditures)
+		assert.Equal(t, []string{MetricCapitalExpenditures}, metrics.MissingComponents)
+		assert.False(t, metrics.Provenance[MetricNetCashFromOperatingActivities][0].FromOtherFiling)
+	})
+
+	t.Run("comparative with fallback", func(t *testing.T) {
+		metrics, err := NewClient(WithFilingFallback()).ParseCashFlowMetricsFromFacts(facts, filing)
+		require.NoError(t, err)
+		assert.Equal(t, Float(5), metrics.CapitalExpenditures)
+		assert.False(t, metrics.Incomplete)
+
+		source := metrics.Provenance[MetricCapitalExpenditures][0]
+		assert.True(t, source.FromOtherFiling)
+		assert.Equal(t, "q1-next", source.AccessionNumber)
+		assert.Contains(t, source.String(), "not reported by the analyzed filing")
+		assert.False(t, metrics.Provenance[MetricNetCashFromOperatingActivities][0].FromOtherFiling)
+	})
+}
@@ -0,0 +1,291 @@
+package edgar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// IncomeStatement represents a standardized income statement for one period. Values that could not be found are nil.
+type IncomeStatement struct {
+	Revenue                         *float64 `json:"revenue"`
+	CostOfRevenue                   *float64 `json:"costOfRevenue"`
+	GrossProfit                     *float64 `json:"grossProfit"` // Revenue minus cost of revenue when not reported
+	SellingGeneralAndAdministrative *float64 `json:"sellingGeneralAndAdministrative"`
+	ResearchAndDevelopment          *float64 `json:"researchAndDevelopment"`
+	OperatingIncome                 *float64 `json:"operatingIncome"`
+	InterestExpense                 *float64 `json:"interestExpense"`
+	IncomeTaxExpense                *float64 `json:"incomeTaxExpense"`
+	NetIncome                       *float64 `json:"netIncome"`
+	EPSBasic                        *float64 `json:"epsBasic"`
+	EPSDiluted                      *float64 `json:"epsDiluted"`
+	SharesBasic                     *float64 `json:"sharesBasic"` // Weighted average shares outstanding
+	SharesDiluted                   *float64 `json:"sharesDiluted"`
+	MetricStatus
+}
+
+// CashFlowStatement represents a standardized cash flow statement for one period. Outflows such as capital
+// expenditures, dividends and buybacks are positive amounts, as reported; working capital changes are their
+// effect on cash. Values that could not be found are nil.
+type CashFlowStatement struct {
+	NetIncome                      *float64 `json:"netIncome"`
+	DepreciationAndAmortization    *float64 `json:"depreciationAndAmortization"`
+	StockBasedCompensation         *float64 `json:"stockBasedCompensation"`
+	ChangeInReceivables            *float64 `json:"changeInReceivables"`
+	ChangeInInventories            *float64 `json:"changeInInventories"`
+	ChangeInPayables               *float64 `json:"changeInPayables"`
+	ChangeInWorkingCapital         *float64 `json:"changeInWorkingCapital"` // Sum of the changes above that were reported, when no total was
+	NetCashFromOperatingActivities *float64 `json:"netCashFromOperatingActivities"`
+	CapitalExpenditures            *float64 `json:"capitalExpenditures"`
+	NetCashFromInvestingActivities *float64 `json:"netCashFromInvestingActivities"`
+	DividendsPaid                  *float64 `json:"dividendsPaid"`
+	ShareRepurchases               *float64 `json:"shareRepurchases"`
+	NetCashFromFinancingActivities *float64 `json:"netCashFromFinancingActivities"`
+	FreeCashFlow                   *float64 `json:"freeCashFlow"` // Requires operating cash flow; missing capex counts as zero, see IncompleteTotals
+	MetricStatus
+}
+
+// PeriodStatements holds the income and cash flow statements of one period, as reported by a filing
+type PeriodStatements struct {
+	FilingDate        string            `json:"filingDate"`
+	ReportDate        string            `json:"reportDate"`
+	Form              string            `json:"form"`
+	AccessionNumber   string            `json:"accessionNumber"`
+	Period            string            `json:"period"`   // Length of the period, "3M" or "12M"
+	Currency          string            `json:"currency"` // Reporting currency of the company facts, e.g. "USD"
+	IncomeStatement   IncomeStatement   `json:"incomeStatement"`
+	CashFlowStatement CashFlowStatement `json:"cashFlowStatement"`
+}
+
+// FinancialStatements represents a company's statements side by side, most recent period first
+type FinancialStatements struct {
+	CompanyName string             `json:"companyName"`
+	CIK         string             `json:"cik"`
+	Periods     []PeriodStatements `json:"periods"`
+}
+
+// GetFinancialStatements retrieves the income and cash flow statements of the most recent fiscal quarters,
+// one per 10-Q or 10-K filing. The fourth quarter of a 10-K is derived from its 12-month values.
+func (c *Client) GetFinancialStatements(ctx context.Context, cik string, periods int) (*FinancialStatements, error) {
+	if periods < 1 {
+		return nil, fmt.Errorf("periods must be at least 1, got %d", periods)
+	}
+
+	filings, err := c.GetMostRecentQuarterlyFilings(ctx, cik, periods)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent quarterly filings: %w", err)
+	}
+
+	return c.financialStatements(ctx, cik, filings, DurationQuarter)
+}
+
+// GetAnnualFinancialStatements retrieves the income and cash flow statements of the most recent fiscal years,
+// one per annual report (10-K, 20-F or 40-F)
+func (c *Client) GetAnnualFinancialStatements(ctx context.Context, cik string, years int) (*FinancialStatements, error) {
+	filings, err := c.GetMostRecentAnnualFilings(ctx, cik, years)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent annual filings: %w", err)
+	}
+
+	return c.financialStatements(ctx, cik, filings, DurationYear)
+}
+
+// financialStatements parses the statements of each filing for the period of the given duration
+func (c *Client) financialStatements(ctx context.Context, cik string, filings []Filing, duration PeriodDuration) (*FinancialStatements, error) {
+	// Get company facts once (we'll reuse this for all periods)
+	facts, err := c.GetCompanyFacts(ctx, cik)
+	if err != nil {
+		return nil, fmt.Errorf("error getting company facts: %w", err)
+	}
+
+	statements := &FinancialStatements{
+		CompanyName: facts.Entity,
+		CIK:         facts.GetCIKString(),
+		Periods:     make([]PeriodStatements, 0, len(filings)),
+	}
+	concepts := c.companyConcepts(ctx, cik)
+
+	for _, filing := range filings {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		period, err := c.parseFinancialStatements(facts, &filing, duration, concepts)
+		if err != nil {
+			log.Printf("Warning: Could not parse financial statements for filing %s: %v", filing.AccessionNumber, err)
+			continue
+		}
+		statements.Periods = append(statements.Periods, *period)
+	}
+
+	if len(statements.Periods) == 0 {
+		return nil, fmt.Errorf("no financial statements could be extracted from any filings")
+	}
+
+	return statements, nil
+}
+
+// ParseFinancialStatementsFromFacts extracts the income and cash flow statements for the period of the given
+// duration (DurationQuarter or DurationYear) ending on the filing's report date using pre-fetched company facts
+func (c *Client) ParseFinancialStatementsFromFacts(facts *CompanyFacts, filing *Filing, duration PeriodDuration) (*PeriodStatements, error) {
+	return c.parseFinancialStatements(facts, filing, duration, c.conceptsFor(facts.GetCIKString(), ""))
+}
+
+// parseFinancialStatements extracts the statements for the period of the given duration ending on the
+// filing's report date, reading each line item from its candidates in concepts
+func (c *Client) parseFinancialStatements(facts *CompanyFacts, filing *Filing, duration PeriodDuration, concepts map[string][]ConceptCandidate) (*PeriodStatements, error) {
+	period := &PeriodStatements{
+		FilingDate:      filing.FilingDate.String(),
+		ReportDate:      filing.ReportDate.String(),
+		Form:            filing.Form,
+		AccessionNumber: filing.AccessionNumber,
+		Period:          duration.String(),
+		Currency:        facts.ReportingCurrency(),
+	}
+
+	if filing.ReportDate.IsZero() {
+		return nil, fmt.Errorf("filing %s has no report date", filing.AccessionNumber)
+	}
+	if facts.Facts == nil {
+		return nil, fmt.Errorf("facts data is nil")
+	}
+
+	if err := checkTaxonomies(facts, concepts); err != nil {
+		return nil, fmt.Errorf("error extracting financial statements: %w", err)
+	}
+
+	sel := c.forFiling(filing, duration)
+	c.extractIncomeStatement(facts, concepts, sel, &period.IncomeStatement)
+	c.extractCashFlowStatement(facts, concepts, sel, &period.CashFlowStatement)
+
+	return period, nil
+}
+
+// statementItem is a line item of a statement, named by its JSON field
+type statementItem struct {
+	name  string
+	value **float64
+}
+
+// extractItems extracts each line item into its value, recording missing items and sources in status.
+// Items in optional are not marked missing, so that the caller can try to derive them instead.
+func (c *Client) extractItems(facts *CompanyFacts, concepts map[string][]ConceptCandidate, sel FactSelector, items []statementItem, status *MetricStatus, optional ...string) {
+	for _, item := range items {
+		source, err := c.extractMetric(facts, concepts[item.name], sel)
+		if err != nil {
+			if !slices.Contains(optional, item.name) {
+				log.Printf("Warning: Could not extract %s: %v", item.name, err)
+				status.addMissing(item.name)
+			}
+			continue
+		}
+
+		*item.value = Float(source.Value)
+		status.addSource(item.name, source)
+		if source.Derivation != "" {
+			status.addDerivation(item.name, source.Derivation)
+		}
+	}
+}
+
+// extractIncomeStatement extracts the income statement for the period selected by sel
+func (c *Client) extractIncomeStatement(facts *CompanyFacts, concepts map[string][]ConceptCandidate, sel FactSelector, is *IncomeStatement) {
+	c.extractItems(facts, concepts, sel, []statementItem{
+		{MetricRevenue, &is.Revenue},
+		{MetricCostOfRevenue, &is.CostOfRevenue},
+		{MetricGrossProfit, &is.GrossProfit},
+		{MetricSellingGeneralAndAdministrative, &is.SellingGeneralAndAdministrative},
+		{MetricResearchAndDevelopment, &is.ResearchAndDevelopment},
+		{MetricOperatingIncome, &is.OperatingIncome},
+		{MetricInterestExpense, &is.InterestExpense},
+		{MetricIncomeTaxExpense, &is.IncomeTaxExpense},
+		{MetricNetIncome, &is.NetIncome},
+		{MetricEPSBasic, &is.EPSBasic},
+		{MetricEPSDiluted, &is.EPSDiluted},
+		{MetricSharesBasic, &is.SharesBasic},
+		{MetricSharesDiluted, &is.SharesDiluted},
+	}, &is.MetricStatus, MetricGrossProfit)
+
+	// Per-share amounts and share counts cannot be derived from year-to-date values, so a quarter the
+	// filing only reported year-to-date, such as the fourth quarter of a 10-K, has none
+	if sel.Duration == DurationQuarter {
+		for _, item := range []statementItem{
+			{MetricEPSBasic, &is.EPSBasic},
+			{MetricEPSDiluted, &is.EPSDiluted},
+			{MetricSharesBasic, &is.SharesBasic},
+			{MetricSharesDiluted, &is.SharesDiluted},
+		} {
+			if *item.value != nil {
+				continue
+			}
+			ytd := sel
+			ytd.Duration = DurationAny
+			if source, err := c.extractMetric(facts, concepts[item.name], ytd); err == nil {
+				period := Fact{Start: source.Start, End: source.End}
+				is.addMissingReason(item.name, fmt.Sprintf("only reported for %s to %s, and per-share amounts and share counts cannot be derived from year-to-date values", period.Duration(), period.End))
+			}
+		}
+	}
+
+	if is.GrossProfit != nil {
+		return
+	}
+	if is.Revenue == nil || is.CostOfRevenue == nil {
+		is.addMissing(MetricGrossProfit)
+		return
+	}
+	is.GrossProfit = Float(*is.Revenue - *is.CostOfRevenue)
+	is.addDerivation(MetricGrossProfit, MetricRevenue+" minus "+MetricCostOfRevenue)
+}
+
+// extractCashFlowStatement extracts the cash flow statement for the period selected by sel
+func (c *Client) extractCashFlowStatement(facts *CompanyFacts, concepts map[string][]ConceptCandidate, sel FactSelector, cf *CashFlowStatement) {
+	c.extractItems(facts, concepts, sel, []statementItem{
+		{MetricNetIncome, &cf.NetIncome},
+		{MetricStockBasedCompensation, &cf.StockBasedCompensation},
+		{MetricChangeInReceivables, &cf.ChangeInReceivables},
+		{MetricChangeInInventories, &cf.ChangeInInventories},
+		{MetricChangeInPayables, &cf.ChangeInPayables},
+		{MetricChangeInWorkingCapital, &cf.ChangeInWorkingCapital},
+		{MetricNetCashFromOperatingActivities, &cf.NetCashFromOperatingActivities},
+		{MetricCapitalExpenditures, &cf.CapitalExpenditures},
+		{MetricNetCashFromInvestingActivities, &cf.NetCashFromInvestingActivities},
+		{MetricDividendsPaid, &cf.DividendsPaid},
+		{MetricShareRepurchases, &cf.ShareRepurchases},
+		{MetricNetCashFromFinancingActivities, &cf.NetCashFromFinancingActivities},
+	}, &cf.MetricStatus, MetricChangeInWorkingCapital)
+
+	// D&A as in EBITDAMetrics, summing depreciation and amortization when no combined figure was reported
+	c.extractDepreciationAndAmortization(facts, concepts, sel, &cf.DepreciationAndAmortization, &cf.MetricStatus)
+
+	// Sum the individual working capital changes when no total was reported. Changes that were not
+	// reported count as zero and are listed in IncompleteTotals.
+	if cf.ChangeInWorkingCapital == nil {
+		parts := []totalInput{
+			{MetricChangeInReceivables, cf.ChangeInReceivables},
+			{MetricChangeInInventories, cf.ChangeInInventories},
+			{MetricChangeInPayables, cf.ChangeInPayables},
+		}
+
+		var total float64
+		var summed []string
+		for _, part := range parts {
+			if part.value != nil {
+				total += *part.value
+				summed = append(summed, part.name)
+			}
+		}
+
+		if len(summed) == 0 {
+			cf.addMissing(MetricChangeInWorkingCapital)
+		} else {
+			cf.ChangeInWorkingCapital = Float(total)
+			cf.addDerivation(MetricChangeInWorkingCapital, strings.Join(summed, " plus "))
+			cf.checkTotal(MetricChangeInWorkingCapital, parts...)
+		}
+	}
+
+	// Free cash flow, as in CashFlowMetrics
+	cf.FreeCashFlow = freeCashFlow(cf.NetCashFromOperatingActivities, cf.CapitalExpenditures, &cf.MetricStatus)
+}
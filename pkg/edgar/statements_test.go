@@ -0,0 +1,209 @@
+package edgar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ParseFinancialStatementsFromFacts(t *testing.T) {
+	q1 := NewDate(2023, time.December, 30)
+	q2 := NewDate(2024, time.March, 30)
+	q2Start := NewDate(2023, time.December, 31)
+
+	// quarter builds a fact for the second fiscal quarter
+	quarter := func(val float64) Fact {
+		return Fact{Start: q2Start, End: q2, Val: val, Accn: "q2", Form: "10-Q", Filed: NewDate(2024, time.May, 3)}
+	}
+	concept := func(unit string, facts ...Fact) *Concept {
+		return &Concept{Units: map[string][]Fact{unit: facts}}
+	}
+	// ytd builds the 3M and 6M year-to-date facts of a cash flow item
+	ytd := func(q1Val, q2Val float64) *Concept {
+		return concept("USD", filedBy("q1", ytdFact(q1, q1Val, "10-Q", "2024-02-02")), filedBy("q2", ytdFact(q2, q2Val, "10-Q", "2024-05-03")))
+	}
+
+	facts := &CompanyFacts{CIK: "320193", Entity: "Apple Inc.", Facts: map[string]Taxonomy{"us-gaap": {
+		"Revenues":                               concept("USD", quarter(90)),
+		"CostOfGoodsAndServicesSold":             concept("USD", quarter(48)),
+		"SellingGeneralAndAdministrativeExpense": concept("USD", quarter(6)),
+		"ResearchAndDevelopmentExpense":          concept("USD", quarter(8)),
+		"OperatingIncomeLoss":                    concept("USD", quarter(28)),
+		"IncomeTaxExpenseBenefit":                concept("USD", quarter(4)),
+		"NetIncomeLoss":                          concept("USD", quarter(24), filedBy("q1", ytdFact(q1, 34, "10-Q", "2024-02-02")), filedBy("q2", ytdFact(q2, 58, "10-Q", "2024-05-03"))),
+		"EarningsPerShareBasic":                  concept("USD/shares", quarter(1.53)),
+		"EarningsPerShareDiluted":                concept("USD/shares", quarter(1.52)),
+		// Weighted average shares reported year-to-date only cannot be turned into a quarter
+		"WeightedAverageNumberOfSharesOutstandingBasic": concept("shares", filedBy("q1", ytdFact(q1, 15500, "10-Q", "2024-02-02")), filedBy("q2", ytdFact(q2, 15450, "10-Q", "2024-05-03"))),
+
+		"DepreciationDepletionAndAmortization":       ytd(3, 5),
+		"ShareBasedCompensation":                     ytd(3, 5),
+		"IncreaseDecreaseInAccountsReceivable":       ytd(-10, -2), // A decrease in receivables adds cash
+		"IncreaseDecreaseInInventories":              ytd(1, 2),
+		"IncreaseDecreaseInAccountsPayable":          ytd(-3, -12),
+		"NetCashProvidedByUsedInOperatingActivities": ytd(40, 63),
+		"PaymentsToAcquirePropertyPlantAndEquipment": ytd(2, 4),
+		"NetCashProvidedByUsedInInvestingActivities": ytd(1, -1),
+		"PaymentsOfDividends":                        ytd(4, 8),
+		"PaymentsForRepurchaseOfCommonStock":         ytd(20, 40),
+		"NetCashProvidedByUsedInFinancingActivities": ytd(-30, -57),
+	}}}
+
+	period, err := NewClient().ParseFinancialStatementsFromFacts(facts, &Filing{AccessionNumber: "q2", Form: "10-Q", ReportDate: q2}, DurationQuarter)
+	require.NoError(t, err)
+	assert.Equal(t, "3M", period.Period)
+	assert.Equal(t, "2024-03-30", period.ReportDate)
+
+	is := period.IncomeStatement
+	assert.Equal(t, Float(90), is.Revenue)
+	assert.Equal(t, Float(48), is.CostOfRevenue)
+	assert.Equal(t, Float(42), is.GrossProfit)
+	assert.Equal(t, "revenue minus costOfRevenue", is.Derivations[MetricGrossProfit])
+	assert.Equal(t, Float(6), is.SellingGeneralAndAdministrative)
+	assert.Equal(t, Float(8), is.ResearchAndDevelopment)
+	assert.Equal(t, Float(28), is.OperatingIncome)
+	assert.Equal(t, Float(24), is.NetIncome)
+	assert.Equal(t, Float(1.53), is.EPSBasic)
+	assert.Equal(t, Float(1.52), is.EPSDiluted)
+	assert.Nil(t, is.SharesBasic)
+	assert.ElementsMatch(t, []string{MetricInterestExpense, MetricSharesBasic, MetricSharesDiluted}, is.MissingComponents)
+	assert.Equal(t, "only reported for 6M to 2024-03-30, and per-share amounts and share counts cannot be derived from year-to-date values", is.MissingReasons[MetricSharesBasic])
+	assert.NotContains(t, is.MissingReasons, MetricSharesDiluted) // Not reported at all
+
+	cf := period.CashFlowStatement
+	assert.Equal(t, Float(24), cf.NetIncome)
+	assert.Equal(t, Float(2), cf.DepreciationAndAmortization)
+	assert.Equal(t, Float(2), cf.StockBasedCompensation)
+	assert.Equal(t, Float(-8), cf.ChangeInReceivables)
+	assert.Equal(t, Float(-1), cf.ChangeInInventories)
+	assert.Equal(t, Float(-9), cf.ChangeInPayables)
+	assert.Equal(t, Float(-18), cf.ChangeInWorkingCapital)
+	assert.Equal(t, "changeInReceivables plus changeInInventories plus changeInPayables", cf.Derivations[MetricChangeInWorkingCapital])
+	assert.Equal(t, Float(23), cf.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(2), cf.CapitalExpenditures)
+	assert.Equal(t, Float(-2), cf.NetCashFromInvestingActivities)
+	assert.Equal(t, Float(4), cf.DividendsPaid)
+	assert.Equal(t, Float(20), cf.ShareRepurchases)
+	assert.Equal(t, Float(-27), cf.NetCashFromFinancingActivities)
+	assert.Equal(t, Float(21), cf.FreeCashFlow)
+	assert.False(t, cf.Incomplete)
+	assert.Equal(t, "6M to 2024-03-30 minus 3M to 2023-12-30", cf.Derivations[MetricShareRepurchases])
+	assert.Equal(t, SignNegate, cf.Provenance[MetricChangeInReceivables][0].Sign)
+}
+
+func TestClient_ParseFinancialStatementsFromFacts_FourthQuarter(t *testing.T) {
+	fyStart := NewDate(2023, time.October, 1)
+	q4Start := NewDate(2024, time.June, 30)
+	fyEnd := NewDate(2024, time.September, 28)
+
+	// fact builds a fact of the 10-K for the period from start to the fiscal year end
+	fact := func(start Date, val float64) Fact {
+		return Fact{Start: start, End: fyEnd, Val: val, Accn: "fy24", Form: "10-K", Filed: NewDate(2024, time.November, 1)}
+	}
+	concept := func(unit string, facts ...Fact) *Concept {
+		return &Concept{Units: map[string][]Fact{unit: facts}}
+	}
+
+	facts := &CompanyFacts{CIK: "320193", Entity: "Apple Inc.", Facts: map[string]Taxonomy{"us-gaap": {
+		"NetIncomeLoss":         concept("USD", fact(q4Start, 15), fact(fyStart, 94)),
+		"EarningsPerShareBasic": concept("USD/shares", fact(fyStart, 6.11)),
+		// No combined D&A concept, so depreciation and amortization are summed as for EBITDA
+		"DepreciationNonproduction": concept("USD", fact(q4Start, 2)),
+		"Amortization":              concept("USD", fact(q4Start, 1)),
+		// No inventories change is reported
+		"IncreaseDecreaseInAccountsReceivable": concept("USD", fact(q4Start, 5)),
+		"IncreaseDecreaseInAccountsPayable":    concept("USD", fact(q4Start, 7)),
+	}}}
+	filing := &Filing{AccessionNumber: "fy24", Form: "10-K", ReportDate: fyEnd}
+
+	period, err := NewClient().ParseFinancialStatementsFromFacts(facts, filing, DurationQuarter)
+	require.NoError(t, err)
+
+	is := period.IncomeStatement
+	assert.Equal(t, Float(15), is.NetIncome)
+	assert.Nil(t, is.EPSBasic)
+	assert.Contains(t, is.MissingComponents, MetricEPSBasic)
+	assert.Equal(t, "only reported for 12M to 2024-09-28, and per-share amounts and share counts cannot be derived from year-to-date values", is.MissingReasons[MetricEPSBasic])
+
+	cf := period.CashFlowStatement
+	assert.Equal(t, Float(3), cf.DepreciationAndAmortization)
+	assert.Len(t, cf.Provenance[MetricDepreciationAndAmortization], 2)
+	assert.NotContains(t, cf.MissingComponents, MetricDepreciationAndAmortization)
+
+	ebitda, err := NewClient().ParseEBITDAMetricsFromFacts(facts, filing)
+	require.NoError(t, err)
+	assert.Equal(t, ebitda.DepreciationAndAmortization, cf.DepreciationAndAmortization)
+
+	assert.Equal(t, Float(-5), cf.ChangeInReceivables)
+	assert.Nil(t, cf.ChangeInInventories)
+	assert.Equal(t, Float(2), cf.ChangeInWorkingCapital)
+	assert.Equal(t, "changeInReceivables plus changeInPayables", cf.Derivations[MetricChangeInWorkingCapital])
+	assert.Equal(t, []string{MetricChangeInInventories}, cf.IncompleteTotals[MetricChangeInWorkingCapital])
+	assert.NotContains(t, cf.MissingComponents, MetricChangeInWorkingCapital)
+}
+
+func TestClient_ParseFinancialStatementsFromFacts_NonUSDCurrency(t *testing.T) {
+	end := NewDate(2024, time.March, 31)
+	quarter := func(val float64) Fact {
+		return Fact{Start: NewDate(2024, time.January, 1), End: end, Val: val, Accn: "q1", Form: "10-Q", Filed: NewDate(2024, time.May, 10)}
+	}
+	concept := func(unit string, facts ...Fact) *Concept {
+		return &Concept{Units: map[string][]Fact{unit: facts}}
+	}
+
+	// A US-GAAP filer reporting in renminbi
+	facts := &CompanyFacts{CIK: "1577552", Entity: "Alibaba Group Holding Ltd", Facts: map[string]Taxonomy{"us-gaap": {
+		"Revenues":                concept("CNY", quarter(221874)),
+		"NetIncomeLoss":           concept("CNY", quarter(919)),
+		"EarningsPerShareBasic":   concept("CNY/shares", quarter(0.05)),
+		"EarningsPerShareDiluted": concept("CNY/shares", quarter(0.04)),
+	}}}
+
+	period, err := NewClient().ParseFinancialStatementsFromFacts(facts, &Filing{AccessionNumber: "q1", Form: "10-Q", ReportDate: end}, DurationQuarter)
+	require.NoError(t, err)
+
+	is := period.IncomeStatement
+	assert.Equal(t, Float(221874), is.Revenue)
+	assert.Equal(t, Float(0.05), is.EPSBasic)
+	assert.Equal(t, Float(0.04), is.EPSDiluted)
+	assert.NotContains(t, is.MissingComponents, MetricEPSBasic)
+	assert.Equal(t, "CNY/shares", is.Provenance[MetricEPSDiluted][0].Unit)
+}
+
+func TestClient_GetFinancialStatements(t *testing.T) {
+	client := NewClient(WithBaseURL(annualServer(t).URL), WithRateLimiter(nil))
+
+	statements, err := client.GetFinancialStatements(context.Background(), "0000320193", 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Apple Inc.", statements.CompanyName)
+	require.Len(t, statements.Periods, 2)
+	assert.Equal(t, "q1", statements.Periods[0].AccessionNumber)
+	assert.True(t, statements.Periods[0].CashFlowStatement.Incomplete)
+
+	// The fourth quarter is derived from the 10-K
+	q4 := statements.Periods[1]
+	assert.Equal(t, "10-K", q4.Form)
+	assert.Equal(t, "3M", q4.Period)
+	assert.Equal(t, Float(27), q4.CashFlowStatement.NetCashFromOperatingActivities)
+	assert.Equal(t, "12M to 2024-09-28 minus 9M to 2024-06-29", q4.CashFlowStatement.Derivations[MetricNetCashFromOperatingActivities])
+
+	_, err = client.GetFinancialStatements(context.Background(), "0000320193", 0)
+	assert.ErrorContains(t, err, "periods must be at least 1")
+}
+
+func TestClient_GetAnnualFinancialStatements(t *testing.T) {
+	client := NewClient(WithBaseURL(annualServer(t).URL), WithRateLimiter(nil))
+
+	statements, err := client.GetAnnualFinancialStatements(context.Background(), "0000320193", 2)
+	require.NoError(t, err)
+
+	require.Len(t, statements.Periods, 2)
+	assert.Equal(t, "12M", statements.Periods[0].Period)
+	assert.Equal(t, Float(391), statements.Periods[0].IncomeStatement.Revenue)
+	assert.Equal(t, Float(118), statements.Periods[0].CashFlowStatement.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(110), statements.Periods[1].CashFlowStatement.NetCashFromOperatingActivities)
+}
@@ -0,0 +1,32 @@
+package edgar
+
+import "sync/atomic"
+
+// Stats reports request counters for a Client, e.g. for monitoring
+type Stats struct {
+	Requests         uint64 `json:"requests"`         // HTTP requests sent, including retries
+	Retries          uint64 `json:"retries"`          // Attempts that failed and were retried
+	CacheHits        uint64 `json:"cacheHits"`        // Responses served from the cache without a request
+	CacheRevalidated uint64 `json:"cacheRevalidated"` // Cached responses confirmed by a 304 Not Modified
+	CacheMisses      uint64 `json:"cacheMisses"`      // Responses downloaded in full with a cache configured
+}
+
+// clientStats holds the live counters behind Stats
+type clientStats struct {
+	requests         atomic.Uint64
+	retries          atomic.Uint64
+	cacheHits        atomic.Uint64
+	cacheRevalidated atomic.Uint64
+	cacheMisses      atomic.Uint64
+}
+
+// Stats returns a snapshot of the client's request counters
+func (c *Client) Stats() Stats {
+	return Stats{
+		Requests:         c.stats.requests.Load(),
+		Retries:          c.stats.retries.Load(),
+		CacheHits:        c.stats.cacheHits.Load(),
+		CacheRevalidated: c.stats.cacheRevalidated.Load(),
+		CacheMisses:      c.stats.cacheMisses.Load(),
+	}
+}
@@ -1,10 +1,12 @@
 package testutil
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/natedogg/edgar/pkg/edgar"
 )
@@ -22,7 +24,7 @@ type MockClient struct {
 }
 
 // GetCompanyFacts returns the mocked company facts response
-func (m *MockClient) GetCompanyFacts(cik string) (*edgar.CompanyFacts, error) {
+func (m *MockClient) GetCompanyFacts(ctx context.Context, cik string) (*edgar.CompanyFacts, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -30,7 +32,7 @@ func (m *MockClient) GetCompanyFacts(cik string) (*edgar.CompanyFacts, error) {
 }
 
 // GetCompanySubmissions returns the mocked company submissions response
-func (m *MockClient) GetCompanySubmissions(cik string) (*edgar.CompanySubmissions, error) {
+func (m *MockClient) GetCompanySubmissions(ctx context.Context, cik string) (*edgar.CompanySubmissions, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -38,18 +40,18 @@ func (m *MockClient) GetCompanySubmissions(cik string) (*edgar.CompanySubmission
 }
 
 // GetMostRecent10Q returns the first filing from the mocked filings response
-func (m *MockClient) GetMostRecent10Q(cik string) (*edgar.Filing, error) {
+func (m *MockClient) GetMostRecent10Q(ctx context.Context, cik string) (*edgar.Filing, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
 	if len(m.FilingsResp) == 0 {
-		return nil, fmt.Errorf("no 10-Q filings found")
+		return nil, fmt.Errorf("no 10-Q filings found: %w", edgar.ErrNoFilings)
 	}
 	return &m.FilingsResp[0], nil
 }
 
 // GetMostRecent4TenQs returns up to 4 filings from the mocked filings response
-func (m *MockClient) GetMostRecent4TenQs(cik string) ([]edgar.Filing, error) {
+func (m *MockClient) GetMostRecent4TenQs(ctx context.Context, cik string) ([]edgar.Filing, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -63,7 +65,7 @@ func (m *MockClient) GetMostRecent4TenQs(cik string) ([]edgar.Filing, error) {
 }
 
 // ParseCashFlowMetrics returns the mocked cash flow metrics response
-func (m *MockClient) ParseCashFlowMetrics(cik string, filing *edgar.Filing) (*edgar.CashFlowMetrics, error) {
+func (m *MockClient) ParseCashFlowMetrics(ctx context.Context, cik string, filing *edgar.Filing) (*edgar.CashFlowMetrics, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -71,7 +73,7 @@ func (m *MockClient) ParseCashFlowMetrics(cik string, filing *edgar.Filing) (*ed
 }
 
 // ParseEBITDAMetrics returns the mocked EBITDA metrics response
-func (m *MockClient) ParseEBITDAMetrics(cik string, filing *edgar.Filing) (*edgar.EBITDAMetrics, error) {
+func (m *MockClient) ParseEBITDAMetrics(ctx context.Context, cik string, filing *edgar.Filing) (*edgar.EBITDAMetrics, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -79,7 +81,7 @@ func (m *MockClient) ParseEBITDAMetrics(cik string, filing *edgar.Filing) (*edga
 }
 
 // GetQuarterlyCashFlowAnalysis returns the mocked quarterly cash flow analysis
-func (m *MockClient) GetQuarterlyCashFlowAnalysis(cik string) (*edgar.QuarterlyCashFlowAnalysis, error) {
+func (m *MockClient) GetQuarterlyCashFlowAnalysis(ctx context.Context, cik string) (*edgar.QuarterlyCashFlowAnalysis, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -87,7 +89,7 @@ func (m *MockClient) GetQuarterlyCashFlowAnalysis(cik string) (*edgar.QuarterlyC
 }
 
 // GetQuarterlyEBITDAAnalysis returns the mocked quarterly EBITDA analysis
-func (m *MockClient) GetQuarterlyEBITDAAnalysis(cik string) (*edgar.QuarterlyEBITDAAnalysis, error) {
+func (m *MockClient) GetQuarterlyEBITDAAnalysis(ctx context.Context, cik string) (*edgar.QuarterlyEBITDAAnalysis, error) {
 	if m.ErrorToReturn != nil {
 		return nil, m.ErrorToReturn
 	}
@@ -107,84 +109,34 @@ func (p *TestDataProvider) GetMockCompanyFacts() *edgar.CompanyFacts {
 	return &edgar.CompanyFacts{
 		CIK:    "0000320193",
 		Entity: "Apple Inc.",
-		Facts: map[string]interface{}{
-			"us-gaap": map[string]interface{}{
-				"NetCashProvidedByUsedInOperatingActivities": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  50000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
-				},
-				"PaymentsToAcquirePropertyPlantAndEquipment": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  5000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
-				},
-				"Revenues": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  100000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
-				},
-				"NetIncomeLoss": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  25000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
-				},
-				"InterestExpense": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  1000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
-				},
-				"IncomeTaxExpenseBenefit": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  3000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
-				},
-				"DepreciationDepletionAndAmortization": map[string]interface{}{
-					"units": map[string]interface{}{
-						"USD": []interface{}{
-							map[string]interface{}{
-								"form": "10-Q",
-								"val":  2000000000.0,
-								"end":  "2023-12-30",
-							},
-						},
-					},
+		Facts: map[string]edgar.Taxonomy{
+			"us-gaap": {
+				"NetCashProvidedByUsedInOperatingActivities": mockUSDConcept(50000000000.0),
+				"PaymentsToAcquirePropertyPlantAndEquipment": mockUSDConcept(5000000000.0),
+				"Revenues":                             mockUSDConcept(100000000000.0),
+				"NetIncomeLoss":                        mockUSDConcept(25000000000.0),
+				"InterestExpense":                      mockUSDConcept(1000000000.0),
+				"IncomeTaxExpenseBenefit":              mockUSDConcept(3000000000.0),
+				"DepreciationDepletionAndAmortization": mockUSDConcept(2000000000.0),
+			},
+		},
+	}
+}
+
+// mockUSDConcept returns a concept with a single 3-month 10-Q value in USD for the mock report date
+func mockUSDConcept(val float64) *edgar.Concept {
+	return &edgar.Concept{
+		Units: map[string][]edgar.Fact{
+			"USD": {
+				{
+					Start: edgar.NewDate(2023, time.October, 1),
+					End:   edgar.NewDate(2023, time.December, 30),
+					Val:   val,
+					Accn:  "0000320193-24-000007",
+					FY:    2024,
+					FP:    "Q1",
+					Form:  "10-Q",
+					Filed: edgar.NewDate(2024, time.February, 1),
 				},
 			},
 		},
@@ -196,15 +148,7 @@ func (p *TestDataProvider) GetMockCompanySubmissions() *edgar.CompanySubmissions
 	return &edgar.CompanySubmissions{
 		CIK:  "0000320193",
 		Name: "Apple Inc.",
-		Filings: struct {
-			Recent map[string][]interface{} `json:"recent"`
-			Files  []struct {
-				Name        string `json:"name"`
-				FilingCount int    `json:"filingCount"`
-				FilingFrom  string `json:"filingFrom"`
-				FilingTo    string `json:"filingTo"`
-			} `json:"files"`
-		}{
+		Filings: edgar.SubmissionsFilings{
 			Recent: map[string][]interface{}{
 				"accessionNumber":       {"0000320193-24-000007", "0000320193-24-000006", "0000320193-24-000005", "0000320193-24-000004"},
 				"filingDate":            {"2024-02-01", "2023-11-02", "2023-08-03", "2023-05-04"},
@@ -228,57 +172,53 @@ func (p *TestDataProvider) GetMockFilings() []edgar.Filing {
 	return []edgar.Filing{
 		{
 			AccessionNumber: "0000320193-24-000007",
-			FilingDate:      "2024-02-01",
-			ReportDate:      "2023-12-30",
+			FilingDate:      edgar.NewDate(2024, time.February, 1),
+			ReportDate:      edgar.NewDate(2023, time.December, 30),
 			Form:            "10-Q",
 			FileNumber:      "001-36743",
 			FilmNumber:      "24576126",
-			Items:           "",
-			Size:            "100000",
-			IsXBRL:          "1",
-			IsInlineXBRL:    "1",
+			Size:            100000,
+			IsXBRL:          true,
+			IsInlineXBRL:    true,
 			PrimaryDocument: "aapl-20231230.htm",
 			PrimaryDocDesc:  "10-Q",
 		},
 		{
 			AccessionNumber: "0000320193-24-000006",
-			FilingDate:      "2023-11-02",
-			ReportDate:      "2023-09-30",
+			FilingDate:      edgar.NewDate(2023, time.November, 2),
+			ReportDate:      edgar.NewDate(2023, time.September, 30),
 			Form:            "10-Q",
 			FileNumber:      "001-36743",
 			FilmNumber:      "24576125",
-			Items:           "",
-			Size:            "100000",
-			IsXBRL:          "1",
-			IsInlineXBRL:    "1",
+			Size:            100000,
+			IsXBRL:          true,
+			IsInlineXBRL:    true,
 			PrimaryDocument: "aapl-20230930.htm",
 			PrimaryDocDesc:  "10-Q",
 		},
 		{
 			AccessionNumber: "0000320193-24-000005",
-			FilingDate:      "2023-08-03",
-			ReportDate:      "2023-06-30",
+			FilingDate:      edgar.NewDate(2023, time.August, 3),
+			ReportDate:      edgar.NewDate(2023, time.June, 30),
 			Form:            "10-Q",
 			FileNumber:      "001-36743",
 			FilmNumber:      "24576124",
-			Items:           "",
-			Size:            "100000",
-			IsXBRL:          "1",
-			IsInlineXBRL:    "1",
+			Size:            100000,
+			IsXBRL:          true,
+			IsInlineXBRL:    true,
 			PrimaryDocument: "aapl-20230630.htm",
 			PrimaryDocDesc:  "10-Q",
 		},
 		{
 			AccessionNumber: "0000320193-24-000004",
-			FilingDate:      "2023-05-04",
-			ReportDate:      "2023-03-31",
+			FilingDate:      edgar.NewDate(2023, time.May, 4),
+			ReportDate:      edgar.NewDate(2023, time.March, 31),
 			Form:            "10-Q",
 			FileNumber:      "001-36743",
 			FilmNumber:      "24576123",
-			Items:           "",
-			Size:            "100000",
-			IsXBRL:          "1",
-			IsInlineXBRL:    "1",
+			Size:            100000,
+			IsXBRL:          true,
+			IsInlineXBRL:    true,
 			PrimaryDocument: "aapl-20230331.htm",
 			PrimaryDocDesc:  "10-Q",
 		},
@@ -294,9 +234,9 @@ func (p *TestDataProvider) GetMockCashFlowMetrics() *edgar.CashFlowMetrics {
 		ReportDate:                     "2023-12-30",
 		Form:                           "10-Q",
 		AccessionNumber:                "0000320193-24-000007",
-		NetCashFromOperatingActivities: 50000000000,
-		CapitalExpenditures:            5000000000,
-		FreeCashFlow:                   45000000000,
+		NetCashFromOperatingActivities: edgar.Float(50000000000),
+		CapitalExpenditures:            edgar.Float(5000000000),
+		FreeCashFlow:                   edgar.Float(45000000000),
 	}
 }
 
@@ -309,13 +249,13 @@ func (p *TestDataProvider) GetMockEBITDAMetrics() *edgar.EBITDAMetrics {
 		ReportDate:                  "2023-12-30",
 		Form:                        "10-Q",
 		AccessionNumber:             "0000320193-24-000007",
-		Revenue:                     100000000000,
-		NetIncome:                   25000000000,
-		InterestExpense:             1000000000,
-		IncomeTaxExpense:            3000000000,
-		DepreciationAndAmortization: 2000000000,
-		EBITDA:                      31000000000,
-		EBITDAMargin:                31.0,
+		Revenue:                     edgar.Float(100000000000),
+		NetIncome:                   edgar.Float(25000000000),
+		InterestExpense:             edgar.Float(1000000000),
+		IncomeTaxExpense:            edgar.Float(3000000000),
+		DepreciationAndAmortization: edgar.Float(2000000000),
+		EBITDA:                      edgar.Float(31000000000),
+		EBITDAMargin:                edgar.Float(31.0),
 	}
 }
 
@@ -328,13 +268,13 @@ func (p *TestDataProvider) GetMockQuarterlyCashFlowAnalysis() *edgar.QuarterlyCa
 		quarters[i] = edgar.CashFlowMetrics{
 			CompanyName:                    "Apple Inc.",
 			CIK:                            "0000320193",
-			FilingDate:                     filing.FilingDate,
-			ReportDate:                     filing.ReportDate,
+			FilingDate:                     filing.FilingDate.String(),
+			ReportDate:                     filing.ReportDate.String(),
 			Form:                           "10-Q",
 			AccessionNumber:                filing.AccessionNumber,
-			NetCashFromOperatingActivities: 50000000000 - float64(i)*2000000000, // Decreasing trend
-			CapitalExpenditures:            5000000000 + float64(i)*500000000,   // Increasing trend
-			FreeCashFlow:                   45000000000 - float64(i)*2500000000, // Decreasing trend
+			NetCashFromOperatingActivities: edgar.Float(50000000000 - float64(i)*2000000000), // Decreasing trend
+			CapitalExpenditures:            edgar.Float(5000000000 + float64(i)*500000000),   // Increasing trend
+			FreeCashFlow:                   edgar.Float(45000000000 - float64(i)*2500000000), // Decreasing trend
 		}
 	}
 
@@ -358,17 +298,17 @@ func (p *TestDataProvider) GetMockQuarterlyEBITDAAnalysis() *edgar.QuarterlyEBIT
 		quarters[i] = edgar.EBITDAMetrics{
 			CompanyName:                 "Apple Inc.",
 			CIK:                         "0000320193",
-			FilingDate:                  filing.FilingDate,
-			ReportDate:                  filing.ReportDate,
+			FilingDate:                  filing.FilingDate.String(),
+			ReportDate:                  filing.ReportDate.String(),
 			Form:                        "10-Q",
 			AccessionNumber:             filing.AccessionNumber,
-			Revenue:                     revenue,
-			NetIncome:                   netIncome,
-			InterestExpense:             1000000000,
-			IncomeTaxExpense:            3000000000,
-			DepreciationAndAmortization: 2000000000,
-			EBITDA:                      ebitda,
-			EBITDAMargin:                (ebitda / revenue) * 100,
+			Revenue:                     edgar.Float(revenue),
+			NetIncome:                   edgar.Float(netIncome),
+			InterestExpense:             edgar.Float(1000000000),
+			IncomeTaxExpense:            edgar.Float(3000000000),
+			DepreciationAndAmortization: edgar.Float(2000000000),
+			EBITDA:                      edgar.Float(ebitda),
+			EBITDAMargin:                edgar.Float((ebitda / revenue) * 100),
 		}
 	}
 
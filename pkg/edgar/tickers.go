@@ -0,0 +1,355 @@
+package edgar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Request paths of the SEC ticker files, served from the archives host
+const (
+	PathTickersExchange = "/files/company_tickers_exchange.json"
+	PathTickers         = "/files/company_tickers.json"
+)
+
+// TickerFilesTTL is a suitable WithCacheTTL for the ticker files, which the SEC updates daily
+const TickerFilesTTL = 24 * time.Hour
+
+// Company is a listed security from the SEC ticker files
+type Company struct {
+	CIK      int    `json:"cik"`
+	Name     string `json:"name"`
+	Ticker   string `json:"ticker"`
+	Exchange string `json:"exchange,omitempty"` // Empty when loaded from company_tickers.json
+}
+
+// CIKString returns the company's CIK zero-padded to 10 digits
+func (c Company) CIKString() string {
+	return fmt.Sprintf("%010d", c.CIK)
+}
+
+// TickerResolver maps tickers and company names to CIKs using the SEC ticker files.
+// The files are downloaded once per resolver through the client, so they are stored
+// in the client's cache when one is configured.
+type TickerResolver struct {
+	client *Client
+
+	mu        sync.Mutex
+	loaded    bool
+	companies []Company
+	byTicker  map[string]Company
+	byCIK     map[int][]Company
+}
+
+// NewTickerResolver creates a resolver that downloads the ticker files with client
+func NewTickerResolver(client *Client) *TickerResolver {
+	return &TickerResolver{client: client}
+}
+
+// Tickers returns the client's shared ticker resolver
+func (c *Client) Tickers() *TickerResolver {
+	c.tickersOnce.Do(func() {
+		c.tickers = NewTickerResolver(c)
+	})
+	return c.tickers
+}
+
+// ResolveCIK returns the 10-digit CIK for a CIK or ticker symbol
+func (c *Client) ResolveCIK(ctx context.Context, symbolOrCIK string) (string, error) {
+	if cik, err := strconv.Atoi(symbolOrCIK); err == nil {
+		return fmt.Sprintf("%010d", cik), nil
+	}
+
+	company, err := c.Tickers().Lookup(ctx, symbolOrCIK)
+	if err != nil {
+		return "", err
+	}
+
+	return company.CIKString(), nil
+}
+
+// Lookup returns the company listed under ticker. Matching is case-insensitive
+// and treats share class separators alike, so "brk.b" finds BRK-B.
+func (r *TickerResolver) Lookup(ctx context.Context, ticker string) (Company, error) {
+	if err := r.load(ctx); err != nil {
+		return Company{}, err
+	}
+
+	company, ok := r.byTicker[normalizeTicker(ticker)]
+	if !ok {
+		return Company{}, fmt.Errorf("ticker %q: %w", ticker, ErrNotFound)
+	}
+
+	return company, nil
+}
+
+// TickersForCIK returns every ticker listed for the company, primary listing first
+func (r *TickerResolver) TickersForCIK(ctx context.Context, cik string) ([]string, error) {
+	if err := r.load(ctx); err != nil {
+		return nil, err
+	}
+
+	n, err := strconv.Atoi(cik)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIK %q: %w", cik, err)
+	}
+
+	var tickers []string
+	for _, company := range r.byCIK[n] {
+		tickers = append(tickers, company.Ticker)
+	}
+
+	return tickers, nil
+}
+
+// Search returns up to limit companies whose ticker or name best match query,
+// one entry per CIK (all matches if limit <= 0)
+func (r *TickerResolver) Search(ctx context.Context, query string, limit int) ([]Company, error) {
+	if err := r.load(ctx); err != nil {
+		return nil, err
+	}
+
+	type match struct {
+		company Company
+		score   int
+	}
+
+	q := normalizeName(query)
+	ticker := normalizeTicker(query)
+	best := make(map[int]match)
+	for _, company := range r.companies {
+		score := matchScore(q, ticker, company)
+		if score == 0 {
+			continue
+		}
+		if m, ok := best[company.CIK]; !ok || score > m.score {
+			best[company.CIK] = match{company: company, score: score}
+		}
+	}
+
+	matches := make([]match, 0, len(best))
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if len(matches[i].company.Name) != len(matches[j].company.Name) {
+			return len(matches[i].company.Name) < len(matches[j].company.Name)
+		}
+		return matches[i].company.Ticker < matches[j].company.Ticker
+	})
+
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+
+	companies := make([]Company, len(matches))
+	for i, m := range matches {
+		companies[i] = m.company
+	}
+
+	return companies, nil
+}
+
+// matchScore rates how well a company matches a normalized query; 0 means no match
+func matchScore(query, ticker string, company Company) int {
+	if query == "" {
+		return 0
+	}
+
+	name := normalizeName(company.Name)
+	switch {
+	case normalizeTicker(company.Ticker) == ticker:
+		return 100
+	case name == query:
+		return 90
+	case strings.HasPrefix(name, query):
+		return 75
+	case wordsPrefixMatch(strings.Fields(query), strings.Fields(name)):
+		return 60
+	case strings.Contains(name, query):
+		return 50
+	}
+
+	return 0
+}
+
+// wordsPrefixMatch reports whether every query word is a prefix of some name word
+func wordsPrefixMatch(queryWords, nameWords []string) bool {
+	for _, qw := range queryWords {
+		found := false
+		for _, nw := range nameWords {
+			if strings.HasPrefix(nw, qw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(queryWords) > 0
+}
+
+// normalizeTicker upper-cases a ticker and uses "-" as the share class separator, as the SEC files do
+func normalizeTicker(ticker string) string {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	return strings.NewReplacer(".", "-", "/", "-").Replace(ticker)
+}
+
+// normalizeName lower-cases a company name and reduces punctuation to single spaces
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// load downloads the ticker files on first use. Failed loads are retried on the next call.
+func (r *TickerResolver) load(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loaded {
+		return nil
+	}
+
+	companies, err := r.client.fetchTickersExchange(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		var fallbackErr error
+		companies, fallbackErr = r.client.fetchTickers(ctx)
+		if fallbackErr != nil {
+			return fmt.Errorf("error loading company tickers: %w", errors.Join(err, fallbackErr))
+		}
+	}
+
+	r.companies = companies
+	r.byTicker = make(map[string]Company, len(companies))
+	r.byCIK = make(map[int][]Company)
+	for _, company := range companies {
+		key := normalizeTicker(company.Ticker)
+		if _, ok := r.byTicker[key]; !ok {
+			r.byTicker[key] = company
+		}
+		r.byCIK[company.CIK] = append(r.byCIK[company.CIK], company)
+	}
+	r.loaded = true
+
+	return nil
+}
+
+// fetchTickersExchange downloads company_tickers_exchange.json, a table of cik, name, ticker and exchange
+func (c *Client) fetchTickersExchange(ctx context.Context) ([]Company, error) {
+	url := c.archivesURL + PathTickersExchange
+
+	body, err := c.makeRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var table struct {
+		Fields []string            `json:"fields"`
+		Data   [][]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &table); err != nil {
+		return nil, &DecodeError{URL: url, Err: err}
+	}
+
+	columns := make(map[string]int, len(table.Fields))
+	for i, field := range table.Fields {
+		columns[field] = i
+	}
+	for _, field := range []string{"cik", "name", "ticker"} {
+		if _, ok := columns[field]; !ok {
+			return nil, &DecodeError{URL: url, Err: fmt.Errorf("missing field %q", field)}
+		}
+	}
+
+	companies := make([]Company, 0, len(table.Data))
+	for _, row := range table.Data {
+		var company Company
+		if err := decodeColumn(row, columns, "cik", &company.CIK); err != nil {
+			return nil, &DecodeError{URL: url, Err: err}
+		}
+		if err := decodeColumn(row, columns, "name", &company.Name); err != nil {
+			return nil, &DecodeError{URL: url, Err: err}
+		}
+		if err := decodeColumn(row, columns, "ticker", &company.Ticker); err != nil {
+			return nil, &DecodeError{URL: url, Err: err}
+		}
+		if err := decodeColumn(row, columns, "exchange", &company.Exchange); err != nil {
+			return nil, &DecodeError{URL: url, Err: err}
+		}
+		companies = append(companies, company)
+	}
+
+	return companies, nil
+}
+
+// decodeColumn decodes the named column of a row into v, leaving v unchanged for missing or null values
+func decodeColumn(row []json.RawMessage, columns map[string]int, name string, v interface{}) error {
+	i, ok := columns[name]
+	if !ok || i >= len(row) || string(row[i]) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(row[i], v); err != nil {
+		return fmt.Errorf("column %s: %w", name, err)
+	}
+	return nil
+}
+
+// fetchTickers downloads company_tickers.json, an object of cik_str, ticker and title keyed by row number
+func (c *Client) fetchTickers(ctx context.Context) ([]Company, error) {
+	url := c.archivesURL + PathTickers
+
+	body, err := c.makeRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows map[string]struct {
+		CIK    int    `json:"cik_str"`
+		Ticker string `json:"ticker"`
+		Title  string `json:"title"`
+	}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, &DecodeError{URL: url, Err: err}
+	}
+
+	// Rows are keyed "0", "1", ... in the SEC's order, which lists a company's primary ticker first
+	keys := make([]int, 0, len(rows))
+	for key := range rows {
+		n, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, &DecodeError{URL: url, Err: fmt.Errorf("invalid row key %q", key)}
+		}
+		keys = append(keys, n)
+	}
+	sort.Ints(keys)
+
+	companies := make([]Company, 0, len(keys))
+	for _, key := range keys {
+		row := rows[strconv.Itoa(key)]
+		companies = append(companies, Company{CIK: row.CIK, Name: row.Title, Ticker: row.Ticker})
+	}
+
+	return companies, nil
+}
@@ -0,0 +1,176 @@
+package edgar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock company_tickers_exchange.json response
+func getMockTickersExchange() string {
+	return `{
+		"fields": ["cik", "name", "ticker", "exchange"],
+		"data": [
+			[320193, "Apple Inc.", "AAPL", "Nasdaq"],
+			[1652044, "Alphabet Inc.", "GOOGL", "Nasdaq"],
+			[1652044, "Alphabet Inc.", "GOOG", "Nasdaq"],
+			[1067983, "BERKSHIRE HATHAWAY INC", "BRK-B", "NYSE"],
+			[1067983, "BERKSHIRE HATHAWAY INC", "BRK-A", "NYSE"],
+			[1418091, "Apple Hospitality REIT, Inc.", "APLE", null]
+		]
+	}`
+}
+
+// Mock company_tickers.json response
+func getMockTickers() string {
+	return `{
+		"0": {"cik_str": 320193, "ticker": "AAPL", "title": "Apple Inc."},
+		"1": {"cik_str": 1652044, "ticker": "GOOGL", "title": "Alphabet Inc."},
+		"2": {"cik_str": 1652044, "ticker": "GOOG", "title": "Alphabet Inc."}
+	}`
+}
+
+// createTickersServer serves the ticker files, optionally failing the exchange file
+func createTickersServer(exchangeStatus int) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case PathTickersExchange:
+			w.WriteHeader(exchangeStatus)
+			if exchangeStatus == http.StatusOK {
+				_, _ = w.Write([]byte(getMockTickersExchange())) // Ignoring write error in test
+			}
+		case PathTickers:
+			_, _ = w.Write([]byte(getMockTickers())) // Ignoring write error in test
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server, &requests
+}
+
+func TestTickerResolver_Lookup(t *testing.T) {
+	server, requests := createTickersServer(http.StatusOK)
+	defer server.Close()
+
+	resolver := NewTickerResolver(NewClient(WithArchivesURL(server.URL)))
+
+	tests := []struct {
+		ticker   string
+		expected string
+	}{
+		{"AAPL", "0000320193"},
+		{"aapl", "0000320193"},
+		{"BRK.B", "0001067983"},
+		{"brk/a", "0001067983"},
+		{"GOOG", "0001652044"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ticker, func(t *testing.T) {
+			company, err := resolver.Lookup(context.Background(), tt.ticker)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, company.CIKString())
+		})
+	}
+
+	_, err := resolver.Lookup(context.Background(), "NOPE")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests), "ticker file should be downloaded once")
+}
+
+func TestTickerResolver_TickersForCIK(t *testing.T) {
+	server, _ := createTickersServer(http.StatusOK)
+	defer server.Close()
+
+	resolver := NewTickerResolver(NewClient(WithArchivesURL(server.URL)))
+
+	tickers, err := resolver.TickersForCIK(context.Background(), "0001652044")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GOOGL", "GOOG"}, tickers)
+
+	tickers, err = resolver.TickersForCIK(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Empty(t, tickers)
+
+	_, err = resolver.TickersForCIK(context.Background(), "apple")
+	assert.Error(t, err)
+}
+
+func TestTickerResolver_Search(t *testing.T) {
+	server, _ := createTickersServer(http.StatusOK)
+	defer server.Close()
+
+	resolver := NewTickerResolver(NewClient(WithArchivesURL(server.URL)))
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"ticker", "aple", []string{"APLE"}},
+		{"name prefix", "apple", []string{"AAPL", "APLE"}},
+		{"word prefixes", "berk hath", []string{"BRK-B"}},
+		{"substring", "hospitality", []string{"APLE"}},
+		{"one result per company", "alphabet", []string{"GOOGL"}},
+		{"no match", "zzz", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			companies, err := resolver.Search(context.Background(), tt.query, 0)
+			require.NoError(t, err)
+
+			var tickers []string
+			for _, company := range companies {
+				tickers = append(tickers, company.Ticker)
+			}
+			assert.Equal(t, tt.expected, tickers)
+		})
+	}
+
+	companies, err := resolver.Search(context.Background(), "apple", 1)
+	require.NoError(t, err)
+	assert.Len(t, companies, 1)
+}
+
+func TestTickerResolver_FallsBackToCompanyTickers(t *testing.T) {
+	server, _ := createTickersServer(http.StatusNotFound)
+	defer server.Close()
+
+	resolver := NewTickerResolver(NewClient(WithArchivesURL(server.URL)))
+
+	company, err := resolver.Lookup(context.Background(), "GOOG")
+	require.NoError(t, err)
+	assert.Equal(t, "Alphabet Inc.", company.Name)
+	assert.Empty(t, company.Exchange)
+
+	tickers, err := resolver.TickersForCIK(context.Background(), "1652044")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GOOGL", "GOOG"}, tickers)
+}
+
+func TestClient_ResolveCIK(t *testing.T) {
+	server, _ := createTickersServer(http.StatusOK)
+	defer server.Close()
+
+	client := NewClient(WithArchivesURL(server.URL))
+
+	cik, err := client.ResolveCIK(context.Background(), "320193")
+	require.NoError(t, err)
+	assert.Equal(t, "0000320193", cik)
+
+	cik, err = client.ResolveCIK(context.Background(), "brk.b")
+	require.NoError(t, err)
+	assert.Equal(t, "0001067983", cik)
+
+	_, err = client.ResolveCIK(context.Background(), "NOPE")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
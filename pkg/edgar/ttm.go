@@ -0,0 +1,294 @@
+package edgar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// TTMMethod describes how a trailing-twelve-month value was computed
+type TTMMethod string
+
+const (
+	TTMFiscalYear TTMMethod = "fiscalYear" // A fiscal year ends on the as-of date, so its 12-month value is used
+	TTMYearToDate TTMMethod = "yearToDate" // Latest fiscal year plus current year-to-date minus prior-year year-to-date
+	TTMQuarters   TTMMethod = "quarters"   // Sum of four discrete quarters
+	TTMMixed      TTMMethod = "mixed"      // Parts summed from several concepts used different methods
+)
+
+// TTMMetrics represents cash flow and EBITDA metrics for the twelve months ending on a filing's report date.
+// Each component records how it was computed in Methods and the periods combined in Periods; its facts
+// are listed in the Provenance of CashFlow or EBITDA.
+type TTMMetrics struct {
+	CompanyName     string               `json:"companyName"`
+	CIK             string               `json:"cik"`
+	AsOf            string               `json:"asOf"` // Last day of the twelve months
+	Form            string               `json:"form"`
+	AccessionNumber string               `json:"accessionNumber"`
+	Methods         map[string]TTMMethod `json:"methods"` // Keyed by component, e.g. "revenue"
+	Periods         map[string]string    `json:"periods"` // e.g. "12M to 2024-09-28 plus 3M to 2024-12-28 minus 3M to 2023-12-30"
+	CashFlow        CashFlowMetrics      `json:"cashFlow"`
+	EBITDA          EBITDAMetrics        `json:"ebitda"`
+}
+
+// ttmValue is a trailing-twelve-month value of a concept and the facts it was computed from
+type ttmValue struct {
+	value   float64
+	method  TTMMethod
+	parts   []ttmPart
+	periods string
+}
+
+// ttmPart is a fact added to, or subtracted from, a trailing-twelve-month value
+type ttmPart struct {
+	fact            Fact
+	derivation      string // Set for quarters derived from year-to-date facts, or DerivationSubtracted
+	fromOtherFiling bool   // Set when the selected filing did not report the period, see FactSelector.Fallback
+}
+
+// trailingTwelveMonths computes the value of a duration concept for the twelve months ending on sel.End,
+// preferring the facts of sel's filing. It uses a 12-month fact ending on that date if there is one,
+// then the latest fiscal year plus the current year-to-date minus the same period of the prior year,
+// and finally the sum of four discrete quarters (see discreteQuarter).
+func trailingTwelveMonths(facts []Fact, sel FactSelector) (ttmValue, bool) {
+	sel.Duration = DurationYear
+	if year, ok := sel.Select(facts); ok {
+		return ttmValue{
+			value:   year.Val,
+			method:  TTMFiscalYear,
+			parts:   []ttmPart{{fact: year, fromOtherFiling: sel.FromOtherFiling(year)}},
+			periods: fmt.Sprintf("%s to %s", year.Duration(), year.End),
+		}, true
+	}
+
+	for _, duration := range []PeriodDuration{DurationNineMonths, DurationHalfYear, DurationQuarter} {
+		sel.Duration = duration
+		ytd, ok := sel.Select(facts)
+		if !ok {
+			continue
+		}
+
+		// The fiscal year ending the day before the year-to-date period starts
+		fiscalYear, ok := latestFiled(facts, func(f Fact) bool {
+			return f.Duration() == DurationYear && f.End.AddDate(0, 0, 1).Equal(ytd.Start.Time)
+		})
+		if !ok {
+			continue
+		}
+
+		// The same year-to-date period of that fiscal year
+		prior, ok := latestFiled(facts, func(f Fact) bool {
+			return f.Start.Equal(fiscalYear.Start.Time) && f.Duration() == duration
+		})
+		if !ok {
+			continue
+		}
+
+		return ttmValue{
+			value:  fiscalYear.Val + ytd.Val - prior.Val,
+			method: TTMYearToDate,
+			parts:  []ttmPart{{fact: fiscalYear}, {fact: ytd, fromOtherFiling: sel.FromOtherFiling(ytd)}, {fact: prior, derivation: DerivationSubtracted}},
+			periods: fmt.Sprintf("%s to %s plus %s to %s minus %s to %s",
+				fiscalYear.Duration(), fiscalYear.End, ytd.Duration(), ytd.End, prior.Duration(), prior.End),
+		}, true
+	}
+
+	// Four quarters, each ending the day before the following one starts. The earlier quarters were
+	// reported by earlier filings.
+	v := ttmValue{method: TTMQuarters}
+	periods := make([]string, 0, 4)
+	end := sel.End
+	for i := 0; i < 4; i++ {
+		quarter, derivation, ok := discreteQuarter(facts, FactSelector{AccessionNumber: sel.AccessionNumber, End: end, Fallback: sel.Fallback || i > 0})
+		if !ok {
+			return ttmValue{}, false
+		}
+		v.value += quarter.Val
+		v.parts = append(v.parts, ttmPart{fact: quarter, derivation: derivation, fromOtherFiling: i == 0 && sel.FromOtherFiling(quarter)})
+		periods = append(periods, fmt.Sprintf("%s to %s", quarter.Duration(), quarter.End))
+		end = Date{quarter.Start.AddDate(0, 0, -1)}
+	}
+	v.periods = strings.Join(periods, " plus ")
+	return v, true
+}
+
+// GetTTMMetrics computes trailing-twelve-month cash flow and EBITDA metrics as of the company's most
+// recent 10-Q or annual report
+func (c *Client) GetTTMMetrics(ctx context.Context, cik string) (*TTMMetrics, error) {
+	filings, err := c.ListFilings(ctx, cik, FilingQuery{Forms: append([]string{"10-Q"}, AnnualForms...), Limit: 1})
+	if err != nil {
+		return nil, fmt.Errorf("error getting most recent filing: %w", err)
+	}
+	if len(filings) == 0 {
+		return nil, fmt.Errorf("no 10-Q or annual filings found for CIK %s: %w", cik, ErrNoFilings)
+	}
+
+	facts, err := c.GetCompanyFacts(ctx, cik)
+	if err != nil {
+		return nil, fmt.Errorf("error getting company facts: %w", err)
+	}
+
+	return c.parseTTMMetrics(facts, &filings[0], c.companyConcepts(ctx, cik))
+}
+
+// ParseTTMMetricsFromFacts computes trailing-twelve-month cash flow and EBITDA metrics for the twelve months
+// ending on the filing's report date using pre-fetched company facts
+func (c *Client) ParseTTMMetricsFromFacts(facts *CompanyFacts, filing *Filing) (*TTMMetrics, error) {
+	return c.parseTTMMetrics(facts, filing, c.conceptsFor(facts.GetCIKString(), ""))
+}
+
+// parseTTMMetrics computes trailing-twelve-month metrics for the twelve months ending on the filing's report
+// date, reading each metric from its candidates in concepts
+func (c *Client) parseTTMMetrics(facts *CompanyFacts, filing *Filing, concepts map[string][]ConceptCandidate) (*TTMMetrics, error) {
+	if filing.ReportDate.IsZero() {
+		return nil, fmt.Errorf("filing %s has no report date", filing.AccessionNumber)
+	}
+	if facts.Facts == nil {
+		return nil, fmt.Errorf("facts data is nil")
+	}
+
+	if err := checkTaxonomies(facts, concepts); err != nil {
+		return nil, fmt.Errorf("error extracting trailing twelve months: %w", err)
+	}
+
+	currency := facts.ReportingCurrency()
+	m := &TTMMetrics{
+		CompanyName:     facts.Entity,
+		CIK:             facts.GetCIKString(),
+		AsOf:            filing.ReportDate.String(),
+		Form:            filing.Form,
+		AccessionNumber: filing.AccessionNumber,
+		Methods:         make(map[string]TTMMethod),
+		Periods:         make(map[string]string),
+		CashFlow: CashFlowMetrics{
+			CompanyName:     facts.Entity,
+			CIK:             facts.GetCIKString(),
+			FilingDate:      filing.FilingDate.String(),
+			ReportDate:      filing.ReportDate.String(),
+			Form:            filing.Form,
+			AccessionNumber: filing.AccessionNumber,
+			Currency:        currency,
+		},
+		EBITDA: EBITDAMetrics{
+			CompanyName:     facts.Entity,
+			CIK:             facts.GetCIKString(),
+			FilingDate:      filing.FilingDate.String(),
+			ReportDate:      filing.ReportDate.String(),
+			Form:            filing.Form,
+			AccessionNumber: filing.AccessionNumber,
+			Currency:        currency,
+		},
+	}
+	sel := c.forFiling(filing, DurationAny)
+
+	for _, component := range []struct {
+		name   string
+		value  **float64
+		status *MetricStatus
+	}{
+		{MetricNetCashFromOperatingActivities, &m.CashFlow.NetCashFromOperatingActivities, &m.CashFlow.MetricStatus},
+		{MetricCapitalExpenditures, &m.CashFlow.CapitalExpenditures, &m.CashFlow.MetricStatus},
+		{MetricRevenue, &m.EBITDA.Revenue, &m.EBITDA.MetricStatus},
+		{MetricNetIncome, &m.EBITDA.NetIncome, &m.EBITDA.MetricStatus},
+		{MetricInterestExpense, &m.EBITDA.InterestExpense, &m.EBITDA.MetricStatus},
+		{MetricIncomeTaxExpense, &m.EBITDA.IncomeTaxExpense, &m.EBITDA.MetricStatus},
+	} {
+		v, sources, err := c.extractTTM(facts, concepts[component.name], sel)
+		if err != nil {
+			log.Printf("Warning: Could not compute trailing twelve months of %s: %v", component.name, err)
+			component.status.addMissing(component.name)
+			continue
+		}
+		*component.value = Float(v.value)
+		m.record(component.name, v.method, v.periods, sources, component.status)
+	}
+
+	c.extractTTMDepreciation(facts, concepts, sel, m)
+
+	m.CashFlow.calculate()
+	m.EBITDA.calculate()
+
+	return m, nil
+}
+
+// extractTTMDepreciation computes trailing-twelve-month depreciation and amortization, summing
+// depreciation and amortization when no combined concept is found
+func (c *Client) extractTTMDepreciation(facts *CompanyFacts, concepts map[string][]ConceptCandidate, sel FactSelector, m *TTMMetrics) {
+	status := &m.EBITDA.MetricStatus
+
+	v, sources, err := c.extractTTM(facts, concepts[MetricDepreciationAndAmortization], sel)
+	if err == nil {
+		m.EBITDA.DepreciationAndAmortization = Float(v.value)
+		m.record(MetricDepreciationAndAmortization, v.method, v.periods, sources, status)
+		return
+	}
+	log.Printf("Warning: Could not compute trailing twelve months of depreciation and amortization: %v", err)
+
+	var total float64
+	var method TTMMethod
+	var all []Provenance
+	var periods []string
+	for _, part := range []string{MetricDepreciation, MetricAmortization} {
+		v, sources, err := c.extractTTM(facts, concepts[part], sel)
+		if err != nil {
+			continue
+		}
+		total += v.value
+		if method == "" {
+			method = v.method
+		} else if method != v.method {
+			method = TTMMixed
+		}
+		all = append(all, sources...)
+		periods = append(periods, part+" "+v.periods)
+	}
+
+	if method == "" {
+		status.addMissing(MetricDepreciationAndAmortization)
+		return
+	}
+	m.EBITDA.DepreciationAndAmortization = Float(total)
+	m.record(MetricDepreciationAndAmortization, method, strings.Join(periods, "; "), all, status)
+}
+
+// record notes how a component was computed and the facts it came from
+func (m *TTMMetrics) record(name string, method TTMMethod, periods string, sources []Provenance, status *MetricStatus) {
+	m.Methods[name] = method
+	m.Periods[name] = periods
+	for _, source := range sources {
+		status.addSource(name, source)
+	}
+}
+
+// extractTTM returns the trailing-twelve-month value of the first candidate concept with enough facts,
+// adjusted by the candidate's sign rule, and the facts it was computed from
+func (c *Client) extractTTM(facts *CompanyFacts, candidates []ConceptCandidate, sel FactSelector) (ttmValue, []Provenance, error) {
+	currency := facts.ReportingCurrency()
+	for _, candidate := range candidates {
+		concept, ok := facts.Concept(candidate.Taxonomy, candidate.Tag)
+		if !ok || concept == nil {
+			continue
+		}
+
+		for _, unit := range candidate.units(concept, currency) {
+			v, ok := trailingTwelveMonths(concept.Facts(unit), sel)
+			if !ok {
+				continue
+			}
+
+			sources := make([]Provenance, 0, len(v.parts))
+			for _, part := range v.parts {
+				source := newProvenance(candidate.Taxonomy, candidate.Tag, unit, part.fact, part.derivation)
+				source.FromOtherFiling = part.fromOtherFiling
+				source.Value = candidate.apply(part.fact.Val)
+				source.Sign = candidate.Sign
+				source.Override = candidate.override
+				sources = append(sources, source)
+			}
+			v.value = candidate.apply(v.value)
+			return v, sources, nil
+		}
+	}
+	return ttmValue{}, nil, fmt.Errorf("trailing twelve months not found with any of the candidate concepts: %v", candidates)
+}
@@ -0,0 +1,182 @@
+package edgar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fy2023Fact builds a fact for the fiscal year starting 2022-10-02
+func fy2023Fact(end Date, val float64, form, filed string) Fact {
+	f, _ := ParseDate(filed)
+	return Fact{Start: NewDate(2022, time.October, 2), End: end, Val: val, Form: form, Filed: f}
+}
+
+func TestTrailingTwelveMonths(t *testing.T) {
+	fy23 := NewDate(2023, time.September, 30)
+	q1 := NewDate(2023, time.December, 30)
+	q2 := NewDate(2024, time.March, 30)
+	priorQ1 := NewDate(2022, time.December, 31)
+	priorQ2 := NewDate(2023, time.April, 1)
+	priorQ3 := NewDate(2023, time.July, 1)
+
+	annual := []Fact{
+		fy2023Fact(priorQ1, 100, "10-Q", "2023-02-03"),
+		fy2023Fact(priorQ2, 190, "10-Q", "2023-05-05"),
+		fy2023Fact(fy23, 400, "10-K", "2023-11-03"),
+		ytdFact(q1, 110, "10-Q", "2024-02-02"),
+		ytdFact(q2, 205, "10-Q", "2024-05-03"),
+	}
+
+	quarters := []Fact{
+		{Start: NewDate(2023, time.January, 1), End: priorQ2, Val: 90, Form: "10-Q", Filed: NewDate(2023, time.May, 5)},
+		{Start: NewDate(2023, time.April, 2), End: priorQ3, Val: 95, Form: "10-Q", Filed: NewDate(2023, time.August, 4)},
+		{Start: NewDate(2023, time.July, 2), End: fy23, Val: 115, Form: "10-Q", Filed: NewDate(2023, time.November, 3)},
+		ytdFact(q1, 110, "10-Q", "2024-02-02"),
+	}
+
+	tests := []struct {
+		name        string
+		facts       []Fact
+		end         Date
+		wantOK      bool
+		wantVal     float64
+		wantMethod  TTMMethod
+		wantParts   int
+		wantPeriods string
+	}{
+		{
+			name:        "fiscal year end",
+			facts:       annual,
+			end:         fy23,
+			wantOK:      true,
+			wantVal:     400,
+			wantMethod:  TTMFiscalYear,
+			wantParts:   1,
+			wantPeriods: "12M to 2023-09-30",
+		},
+		{
+			name:        "first quarter from 10-K plus 3M minus prior 3M",
+			facts:       annual,
+			end:         q1,
+			wantOK:      true,
+			wantVal:     410,
+			wantMethod:  TTMYearToDate,
+			wantParts:   3,
+			wantPeriods: "12M to 2023-09-30 plus 3M to 2023-12-30 minus 3M to 2022-12-31",
+		},
+		{
+			name:        "second quarter from 10-K plus 6M minus prior 6M",
+			facts:       annual,
+			end:         q2,
+			wantOK:      true,
+			wantVal:     415,
+			wantMethod:  TTMYearToDate,
+			wantParts:   3,
+			wantPeriods: "12M to 2023-09-30 plus 6M to 2024-03-30 minus 6M to 2023-04-01",
+		},
+		{
+			name:        "sum of four quarters without a fiscal year",
+			facts:       quarters,
+			end:         q1,
+			wantOK:      true,
+			wantVal:     410,
+			wantMethod:  TTMQuarters,
+			wantParts:   4,
+			wantPeriods: "3M to 2023-12-30 plus 3M to 2023-09-30 plus 3M to 2023-07-01 plus 3M to 2023-04-01",
+		},
+		{
+			name:   "fewer than four quarters",
+			facts:  quarters[1:],
+			end:    q1,
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, ok := trailingTwelveMonths(tt.facts, FactSelector{End: tt.end})
+
+			assert.Equal(t, tt.wantOK, ok)
+			if !tt.wantOK {
+				return
+			}
+			assert.Equal(t, tt.wantVal, v.value)
+			assert.Equal(t, tt.wantMethod, v.method)
+			assert.Len(t, v.parts, tt.wantParts)
+			assert.Equal(t, tt.wantPeriods, v.periods)
+		})
+	}
+}
+
+func TestClient_ParseTTMMetricsFromFacts(t *testing.T) {
+	fy23 := NewDate(2023, time.September, 30)
+	q1 := NewDate(2023, time.December, 30)
+	priorQ1 := NewDate(2022, time.December, 31)
+
+	usd := func(facts ...Fact) *Concept {
+		return &Concept{Units: map[string][]Fact{"USD": facts}}
+	}
+	facts := &CompanyFacts{CIK: "320193", Entity: "Apple Inc.", Facts: map[string]Taxonomy{"us-gaap": {
+		"NetCashProvidedByUsedInOperatingActivities": usd(
+			fy2023Fact(priorQ1, 30, "10-Q", "2023-02-03"),
+			fy2023Fact(fy23, 110, "10-K", "2023-11-03"),
+			filedBy("q1", ytdFact(q1, 40, "10-Q", "2024-02-02")),
+		),
+		"PaymentsToAcquirePropertyPlantAndEquipment": usd(
+			fy2023Fact(priorQ1, 3, "10-Q", "2023-02-03"),
+			fy2023Fact(fy23, 11, "10-K", "2023-11-03"),
+			filedBy("q1", ytdFact(q1, 2, "10-Q", "2024-02-02")),
+		),
+		"Revenues": usd(
+			fy2023Fact(priorQ1, 117, "10-Q", "2023-02-03"),
+			fy2023Fact(fy23, 383, "10-K", "2023-11-03"),
+			filedBy("q1", ytdFact(q1, 120, "10-Q", "2024-02-02")),
+		),
+		// Only quarterly values were reported
+		"NetIncomeLoss": usd(
+			Fact{Start: NewDate(2023, time.January, 1), End: NewDate(2023, time.April, 1), Val: 24, Filed: NewDate(2023, time.May, 5)},
+			Fact{Start: NewDate(2023, time.April, 2), End: NewDate(2023, time.July, 1), Val: 20, Filed: NewDate(2023, time.August, 4)},
+			Fact{Start: NewDate(2023, time.July, 2), End: fy23, Val: 23, Filed: NewDate(2023, time.November, 3)},
+			filedBy("q1", ytdFact(q1, 34, "10-Q", "2024-02-02")),
+		),
+	}}}
+
+	metrics, err := NewClient().ParseTTMMetricsFromFacts(facts, &Filing{AccessionNumber: "q1", Form: "10-Q", ReportDate: q1})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2023-12-30", metrics.AsOf)
+	assert.Equal(t, Float(120), metrics.CashFlow.NetCashFromOperatingActivities)
+	assert.Equal(t, Float(10), metrics.CashFlow.CapitalExpenditures)
+	assert.Equal(t, Float(110), metrics.CashFlow.FreeCashFlow)
+	assert.False(t, metrics.CashFlow.Incomplete)
+
+	assert.Equal(t, Float(386), metrics.EBITDA.Revenue)
+	assert.Equal(t, Float(101), metrics.EBITDA.NetIncome)
+	assert.Equal(t, Float(101), metrics.EBITDA.EBITDA)
+	assert.True(t, metrics.EBITDA.Incomplete)
+	assert.ElementsMatch(t, []string{MetricInterestExpense, MetricIncomeTaxExpense, MetricDepreciationAndAmortization}, metrics.EBITDA.MissingComponents)
+
+	assert.Equal(t, TTMYearToDate, metrics.Methods[MetricRevenue])
+	assert.Equal(t, TTMQuarters, metrics.Methods[MetricNetIncome])
+	assert.Equal(t, "12M to 2023-09-30 plus 3M to 2023-12-30 minus 3M to 2022-12-31", metrics.Periods[MetricRevenue])
+	require.Len(t, metrics.EBITDA.Provenance[MetricRevenue], 3)
+	assert.Len(t, metrics.EBITDA.Provenance[MetricNetIncome], 4)
+
+	// The prior year-to-date period is marked as subtracted, the fiscal year and current year-to-date are added
+	revenue := metrics.EBITDA.Provenance[MetricRevenue]
+	assert.Empty(t, revenue[0].Derivation)
+	assert.Empty(t, revenue[1].Derivation)
+	assert.Equal(t, DerivationSubtracted, revenue[2].Derivation)
+	assert.Equal(t, "2022-12-31", revenue[2].End.String())
+	assert.Contains(t, revenue[2].String(), ", subtracted")
+	assert.NotContains(t, revenue[0].String(), "subtracted")
+	assert.Len(t, metrics.CashFlow.Provenance[MetricCapitalExpenditures], 3)
+}
+
+func TestClient_ParseTTMMetricsFromFacts_NoReportDate(t *testing.T) {
+	_, err := NewClient().ParseTTMMetricsFromFacts(&CompanyFacts{}, &Filing{AccessionNumber: "x"})
+	assert.ErrorContains(t, err, "has no report date")
+}